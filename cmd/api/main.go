@@ -10,6 +10,8 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"bank-service/internal/acquiring"
+	"bank-service/internal/calendar"
 	"bank-service/internal/config"
 	"bank-service/internal/handler"
 	"bank-service/internal/middleware"
@@ -37,13 +39,19 @@ func main() {
 
 	encryptionService := service.NewEncryptionService(cfg)
 	emailService := service.NewEmailService(cfg.SMTP)
-	cbrService := service.NewCBRService()
+	cbrService := service.NewCBRService(repos.KeyRate, cfg.CBR)
+
+	productionCalendar, err := calendar.Load(cfg.Credit.CalendarFile)
+	if err != nil {
+		log.Fatalf("Failed to load production calendar: %v", err)
+	}
 
 	services := service.NewServices(service.Dependencies{
 		Repos:             repos,
 		EncryptionService: encryptionService,
 		EmailService:      emailService,
 		CBRService:        cbrService,
+		Calendar:          productionCalendar,
 		Config:            cfg,
 	})
 
@@ -56,9 +64,25 @@ func main() {
 
 	handlers.RegisterRoutes(router)
 
-	creditScheduler := scheduler.NewCreditScheduler(services.Credit, log)
+	creditScheduler := scheduler.NewCreditScheduler(services.Credit, services.CreditLine, log)
 	go creditScheduler.Start(12 * time.Hour) // Проверка каждые 12 часов
 
+	keyRateScheduler := scheduler.NewKeyRateScheduler(services.CBR, log)
+	go keyRateScheduler.Start(24 * time.Hour) // Обновление истории ключевой ставки раз в сутки
+
+	cardHoldScheduler := scheduler.NewCardHoldScheduler(services.Card, log)
+	go cardHoldScheduler.Start(time.Hour) // Снятие просроченных холдов каждый час
+
+	var isoListener *acquiring.Listener
+	if cfg.ISO8583.Enabled {
+		isoListener = acquiring.NewListener(services.Card, log, cfg.ISO8583.IdleTimeout)
+		go func() {
+			if err := isoListener.ListenAndServe(":" + cfg.ISO8583.Port); err != nil {
+				log.Errorf("ISO 8583 listener failed: %v", err)
+			}
+		}()
+	}
+
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
 		Handler:      router,
@@ -80,6 +104,12 @@ func main() {
 	log.Info("Shutting down server...")
 
 	creditScheduler.Stop()
+	keyRateScheduler.Stop()
+	cardHoldScheduler.Stop()
+
+	if isoListener != nil {
+		isoListener.Close()
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
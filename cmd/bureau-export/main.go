@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"bank-service/internal/bureau"
+	"bank-service/internal/config"
+	"bank-service/internal/repository"
+	"bank-service/pkg/logger"
+)
+
+// Выгрузка кредитных историй в бюро. Запускается периодически, например из cron:
+//
+//	bureau-export -dir /var/lib/bank/bureau
+func main() {
+	log := logger.NewLogger()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	dir := flag.String("dir", cfg.Bureau.ExportDir, "directory for export files")
+	flag.Parse()
+
+	db, err := repository.NewPostgresDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repos := repository.NewRepositories(db)
+	exporter := bureau.NewExporter(repos.User, repos.Credit, repos.Payment, cfg.Bureau.Source)
+
+	result, err := exporter.Export(*dir, time.Now())
+	if err != nil {
+		log.Fatalf("Failed to export credit histories: %v", err)
+	}
+
+	for _, rejected := range result.Rejected {
+		log.Warnf("Record rejected: %v", rejected)
+	}
+
+	log.Infof("Credit histories exported to %s: %d subjects, %d credits, %d validation errors",
+		result.Path, result.Subjects, result.Credits, len(result.Rejected))
+}
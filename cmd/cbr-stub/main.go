@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"bank-service/internal/cbrstub"
+	"bank-service/pkg/logger"
+)
+
+// Заглушка веб-сервиса ЦБ РФ для разработки без доступа к сети:
+//
+//	cbr-stub -addr :8090
+//	CBR_URL=http://localhost:8090/DailyInfoWebServ/DailyInfo.asmx
+func main() {
+	log := logger.NewLogger()
+
+	addr := flag.String("addr", ":8090", "listen address")
+	flag.Parse()
+
+	log.Infof("CBR stub listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, cbrstub.New()); err != nil {
+		log.Fatalf("CBR stub failed: %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package acquiring
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"bank-service/internal/models"
+	"bank-service/internal/service"
+	"bank-service/pkg/iso8583"
+)
+
+// Listener принимает по TCP сообщения ISO 8583 (0100, 0200, 0400)
+// и проводит их через карточную логику cardService.
+type Listener struct {
+	cardService service.CardService
+	logger      *logrus.Logger
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+func NewListener(cardService service.CardService, logger *logrus.Logger, idleTimeout time.Duration) *Listener {
+	return &Listener{
+		cardService: cardService,
+		logger:      logger,
+		idleTimeout: idleTimeout,
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+func (l *Listener) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return l.Serve(listener)
+}
+
+func (l *Listener) Serve(listener net.Listener) error {
+	l.mu.Lock()
+	l.listener = listener
+	l.mu.Unlock()
+
+	l.logger.Infof("ISO 8583 listener started on %s", listener.Addr())
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			l.mu.Lock()
+			closed := l.closed
+			l.mu.Unlock()
+
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		l.mu.Lock()
+		l.conns[conn] = struct{}{}
+		l.mu.Unlock()
+
+		l.wg.Add(1)
+		go l.handleConn(conn)
+	}
+}
+
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	var err error
+	if l.listener != nil {
+		err = l.listener.Close()
+	}
+	for conn := range l.conns {
+		conn.Close()
+	}
+	l.mu.Unlock()
+
+	l.wg.Wait()
+	l.logger.Info("ISO 8583 listener stopped")
+
+	return err
+}
+
+func (l *Listener) handleConn(conn net.Conn) {
+	defer l.wg.Done()
+	defer func() {
+		l.mu.Lock()
+		delete(l.conns, conn)
+		l.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		if l.idleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(l.idleTimeout))
+		}
+
+		frame, err := iso8583.ReadFrame(conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				l.logger.Infof("ISO 8583 connection %s closed: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+
+		response := l.process(frame)
+		if response == nil {
+			return
+		}
+
+		data, err := response.Pack()
+		if err != nil {
+			l.logger.Errorf("Failed to pack ISO 8583 response: %v", err)
+			return
+		}
+
+		if err := iso8583.WriteFrame(conn, data); err != nil {
+			l.logger.Infof("Failed to write ISO 8583 response: %v", err)
+			return
+		}
+	}
+}
+
+// process возвращает ответ на запрос или nil, если соединение нужно закрыть
+func (l *Listener) process(frame []byte) *iso8583.Message {
+	request, err := iso8583.Unpack(frame)
+	if err != nil {
+		l.logger.Infof("Malformed ISO 8583 message: %v", err)
+
+		// Без корректного MTI ответить нечем, соединение закрывается
+		if len(frame) < 4 {
+			return nil
+		}
+
+		mti := string(frame[:4])
+		if mti != iso8583.MTIAuthorizationRequest && mti != iso8583.MTIFinancialRequest && mti != iso8583.MTIReversalRequest {
+			return nil
+		}
+
+		response := iso8583.NewMessage(iso8583.ResponseMTI(mti))
+		response.Set(39, iso8583.ResponseFormatError)
+		return response
+	}
+
+	terminalRequest, err := toTerminalRequest(request)
+	if err != nil {
+		l.logger.Infof("Invalid ISO 8583 %s request: %v", request.MTI, err)
+		return request.Response(iso8583.ResponseFormatError)
+	}
+
+	var hold models.CardHold
+	switch request.MTI {
+	case iso8583.MTIAuthorizationRequest:
+		hold, err = l.cardService.AuthorizeHold(terminalRequest)
+	case iso8583.MTIFinancialRequest:
+		hold, err = l.cardService.CompleteTerminalPayment(terminalRequest)
+	case iso8583.MTIReversalRequest:
+		hold, err = l.cardService.ReverseTerminalOperation(terminalRequest)
+	default:
+		return request.Response(iso8583.ResponseInvalidTransaction)
+	}
+
+	if err != nil {
+		code := responseCode(err)
+		if code == iso8583.ResponseSystemMalfunction {
+			l.logger.Errorf("ISO 8583 %s RRN %s failed: %v", request.MTI, terminalRequest.RRN, err)
+		} else {
+			l.logger.Infof("ISO 8583 %s RRN %s declined (%s): %v", request.MTI, terminalRequest.RRN, code, err)
+		}
+		return request.Response(code)
+	}
+
+	l.logger.Infof("ISO 8583 %s RRN %s approved, hold %d %s", request.MTI, terminalRequest.RRN, hold.ID, hold.Status)
+
+	response := request.Response(iso8583.ResponseApproved)
+	response.Set(38, hold.AuthCode)
+	return response
+}
+
+func toTerminalRequest(message *iso8583.Message) (models.TerminalRequest, error) {
+	if !message.Has(2) || !message.Has(37) {
+		return models.TerminalRequest{}, errors.New("fields 2 and 37 are required")
+	}
+
+	request := models.TerminalRequest{
+		PAN:          message.Get(2),
+		RRN:          message.Get(37),
+		TerminalID:   message.Get(41),
+		MerchantName: message.Get(43),
+	}
+
+	if message.Has(4) {
+		amount, err := iso8583.ParseAmount(message.Get(4))
+		if err != nil {
+			return models.TerminalRequest{}, err
+		}
+		request.Amount = amount
+	}
+
+	// Поле 14 передается как YYMM, в карте срок хранится как MM/YY
+	if expiry := message.Get(14); expiry != "" {
+		if len(expiry) != 4 {
+			return models.TerminalRequest{}, fmt.Errorf("invalid expiry %q", expiry)
+		}
+		request.Expiry = expiry[2:] + "/" + expiry[:2]
+	}
+
+	return request, nil
+}
+
+func responseCode(err error) string {
+	switch err {
+	case service.ErrCardNotFound:
+		return iso8583.ResponseInvalidCardNumber
+	case service.ErrCardInactive, service.ErrCardBlocked:
+		return iso8583.ResponseRestrictedCard
+	case service.ErrCardExpired, service.ErrCardExpiryMismatch:
+		return iso8583.ResponseExpiredCard
+	case service.ErrInvalidAmount, models.ErrInvalidAmount:
+		return iso8583.ResponseInvalidAmount
+	case service.ErrInsufficientFunds, models.ErrInsufficientFunds:
+		return iso8583.ResponseInsufficientFunds
+	case service.ErrHoldNotFound:
+		return iso8583.ResponseOriginalNotFound
+	case service.ErrDuplicateTransaction:
+		return iso8583.ResponseDuplicate
+	case service.ErrAccountNotFound:
+		return iso8583.ResponseDoNotHonor
+	}
+	return iso8583.ResponseSystemMalfunction
+}
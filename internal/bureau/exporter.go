@@ -0,0 +1,278 @@
+package bureau
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"bank-service/internal/models"
+	"bank-service/internal/repository"
+)
+
+// Статусы кредитов, которые передаются в бюро: выданные кредиты, включая погашенные
+var reportedStatuses = []models.CreditStatus{
+	models.CreditStatusActive,
+	models.CreditStatusOverdue,
+	models.CreditStatusClosed,
+}
+
+// Result - итог выгрузки. Записи, не прошедшие проверку обязательных полей,
+// в файл не попадают и перечисляются в Rejected.
+type Result struct {
+	Path     string
+	Subjects int
+	Credits  int
+	Rejected []ValidationError
+}
+
+// Exporter собирает кредитные истории из кредитов и графиков платежей и записывает
+// их в XML-файл для бюро кредитных историй.
+type Exporter struct {
+	userRepo    repository.UserRepository
+	creditRepo  repository.CreditRepository
+	paymentRepo repository.PaymentRepository
+	source      string
+}
+
+func NewExporter(
+	userRepo repository.UserRepository,
+	creditRepo repository.CreditRepository,
+	paymentRepo repository.PaymentRepository,
+	source string,
+) *Exporter {
+	return &Exporter{
+		userRepo:    userRepo,
+		creditRepo:  creditRepo,
+		paymentRepo: paymentRepo,
+		source:      source,
+	}
+}
+
+// Export формирует отчет на момент now и записывает его в каталог dir
+func (e *Exporter) Export(dir string, now time.Time) (Result, error) {
+	report, rejected, err := e.Build(now)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Subjects: len(report.Subjects), Rejected: rejected}
+	for _, subject := range report.Subjects {
+		result.Credits += len(subject.Credits)
+	}
+
+	result.Path, err = writeReport(report, dir, now)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// Build собирает отчет на момент now и возвращает записи, не прошедшие проверку
+func (e *Exporter) Build(now time.Time) (Report, []ValidationError, error) {
+	creditsByUser := make(map[int64][]models.Credit)
+	for _, status := range reportedStatuses {
+		credits, err := e.creditRepo.GetByStatus(status)
+		if err != nil {
+			return Report{}, nil, err
+		}
+		for _, credit := range credits {
+			creditsByUser[credit.UserID] = append(creditsByUser[credit.UserID], credit)
+		}
+	}
+
+	userIDs := make([]int64, 0, len(creditsByUser))
+	for userID := range creditsByUser {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	report := Report{
+		Version:     FormatVersion,
+		Source:      e.source,
+		ReportDate:  Date(now),
+		GeneratedAt: now.UTC().Truncate(time.Second),
+	}
+
+	var rejected []ValidationError
+	for _, userID := range userIDs {
+		subject := Subject{ID: userID}
+		if user, err := e.userRepo.GetByID(userID); err == nil {
+			subject.FullName = user.FullName
+			subject.Email = user.Email
+		}
+
+		if errs := ValidateSubject(subject); len(errs) > 0 {
+			rejected = append(rejected, errs...)
+			continue
+		}
+
+		credits := creditsByUser[userID]
+		sort.Slice(credits, func(i, j int) bool { return credits[i].ID < credits[j].ID })
+
+		for _, credit := range credits {
+			schedules, err := e.paymentRepo.GetByCreditID(credit.ID)
+			if err != nil {
+				return Report{}, nil, err
+			}
+
+			reported := buildCredit(credit, schedules, now)
+			if errs := ValidateCredit(subject.ID, reported); len(errs) > 0 {
+				rejected = append(rejected, errs...)
+				continue
+			}
+
+			subject.Credits = append(subject.Credits, reported)
+		}
+
+		if len(subject.Credits) > 0 {
+			report.Subjects = append(report.Subjects, subject)
+		}
+	}
+
+	return report, rejected, nil
+}
+
+func buildCredit(credit models.Credit, schedules []models.PaymentSchedule, now time.Time) Credit {
+	reported := Credit{
+		ID:             credit.ID,
+		ContractNumber: fmt.Sprintf("CR-%08d", credit.ID),
+		Status:         credit.Status,
+		ProductID:      credit.ProductID,
+		Currency:       "RUB",
+		Amount:         Money(credit.Amount),
+		InterestRate:   credit.InterestRate,
+		FullCostRate:   credit.FullCostRate,
+		RepaymentType:  credit.RepaymentType,
+		Term:           credit.Term,
+		StartDate:      Date(credit.StartDate),
+		EndDate:        Date(credit.EndDate),
+	}
+
+	var outstandingPrincipal, overdueAmount float64
+	number := 0
+
+	for _, schedule := range schedules {
+		if schedule.Status == models.PaymentStatusCanceled {
+			continue
+		}
+		number++
+
+		installment := Installment{
+			Number:     number,
+			DueDate:    Date(schedule.PaymentDate),
+			Amount:     Money(schedule.Amount),
+			Principal:  Money(schedule.Principal),
+			Interest:   Money(schedule.Interest),
+			Status:     schedule.Status,
+			PaidAmount: Money(schedule.PaidAmount),
+		}
+
+		if schedule.PaidDate != nil {
+			paidDate := Date(*schedule.PaidDate)
+			installment.PaidDate = &paidDate
+		}
+
+		if schedule.PaidAmount > 0 {
+			// Дата частичного платежа не хранится, поэтому берется дата последнего списания
+			paymentDate := Date(schedule.UpdatedAt)
+			if installment.PaidDate != nil {
+				paymentDate = *installment.PaidDate
+			}
+
+			reported.Payments = append(reported.Payments, Payment{
+				Installment: number,
+				Date:        paymentDate,
+				Amount:      Money(schedule.PaidAmount),
+			})
+		}
+
+		reported.Installments = append(reported.Installments, installment)
+
+		if schedule.Status == models.PaymentStatusPending || schedule.Status == models.PaymentStatusOverdue {
+			// Частично внесенная сумма гасит сначала проценты, затем основной долг
+			outstandingPrincipal += schedule.Principal - math.Max(schedule.PaidAmount-schedule.Interest, 0)
+		}
+
+		if overdue, ok := buildOverdue(schedule, number, now); ok {
+			if !overdue.Resolved {
+				overdueAmount += float64(overdue.Amount)
+			}
+			if overdue.DaysPastDue > reported.MaxDaysPastDue {
+				reported.MaxDaysPastDue = overdue.DaysPastDue
+			}
+			reported.Overdues = append(reported.Overdues, overdue)
+		}
+	}
+
+	reported.OutstandingPrincipal = Money(math.Max(outstandingPrincipal, 0))
+	reported.OverdueAmount = Money(overdueAmount)
+
+	return reported
+}
+
+// buildOverdue возвращает текущую просрочку по платежу или погашенную, если платеж
+// внесен позже даты по графику
+func buildOverdue(schedule models.PaymentSchedule, number int, now time.Time) (Overdue, bool) {
+	switch {
+	case schedule.Status == models.PaymentStatusOverdue:
+		return Overdue{
+			Installment: number,
+			DueDate:     Date(schedule.PaymentDate),
+			Amount:      Money(schedule.Outstanding()),
+			DaysPastDue: daysBetween(schedule.PaymentDate, now),
+		}, true
+	case schedule.Status == models.PaymentStatusPaid && schedule.PaidDate != nil:
+		days := daysBetween(schedule.PaymentDate, *schedule.PaidDate)
+		if days <= 0 {
+			return Overdue{}, false
+		}
+
+		resolvedDate := Date(*schedule.PaidDate)
+		return Overdue{
+			Installment:  number,
+			Resolved:     true,
+			DueDate:      Date(schedule.PaymentDate),
+			Amount:       Money(schedule.Amount),
+			DaysPastDue:  days,
+			ResolvedDate: &resolvedDate,
+		}, true
+	}
+
+	return Overdue{}, false
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// writeReport записывает отчет во временный файл и переименовывает его, чтобы
+// в каталоге выгрузки не появлялись недописанные файлы
+func writeReport(report Report, dir string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("credit_history_%s.xml", now.Format("20060102_150405")))
+	tmpPath := path + ".tmp"
+
+	if err := os.WriteFile(tmpPath, append([]byte(xml.Header), data...), 0o644); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,224 @@
+// Package bureau формирует файл передачи кредитных историй в бюро кредитных историй.
+//
+// Формат файла (кодировка UTF-8, версия формата 1.0):
+//
+//	<CreditHistoryReport version="1.0" source="BANK" reportDate="2026-01-31" generatedAt="2026-01-31T03:00:00Z">
+//	  <Subject id="12">                                 заемщик
+//	    <FullName>Иванов Иван Иванович</FullName>      обязательно
+//	    <Email>ivanov@example.com</Email>
+//	    <Credit id="5" contractNumber="CR-00000005">    обязательны оба атрибута
+//	      <Status>ACTIVE</Status>                       ACTIVE, OVERDUE, CLOSED
+//	      <ProductID>1</ProductID>
+//	      <Currency>RUB</Currency>
+//	      <Amount>100000.00</Amount>                    обязательно, больше нуля
+//	      <InterestRate>16.50</InterestRate>
+//	      <FullCostRate>17.800</FullCostRate>
+//	      <RepaymentType>ANNUITY</RepaymentType>        ANNUITY, DIFFERENTIATED
+//	      <Term>12</Term>                               месяцев, обязательно
+//	      <StartDate>2026-01-10</StartDate>             обязательно
+//	      <EndDate>2027-01-10</EndDate>                 обязательно
+//	      <OutstandingPrincipal>80000.00</OutstandingPrincipal>
+//	      <OverdueAmount>0.00</OverdueAmount>
+//	      <MaxDaysPastDue>0</MaxDaysPastDue>
+//	      <Installments>                                график платежей без отмененных строк
+//	        <Installment number="1">
+//	          <DueDate>2026-02-10</DueDate>             обязательно
+//	          <Amount>9100.00</Amount>                  обязательно, больше нуля
+//	          <Principal>7800.00</Principal>
+//	          <Interest>1300.00</Interest>
+//	          <Status>PAID</Status>                     PENDING, PAID, OVERDUE
+//	          <PaidAmount>9100.00</PaidAmount>
+//	          <PaidDate>2026-02-10</PaidDate>
+//	        </Installment>
+//	      </Installments>
+//	      <Payments>                                    внесенные суммы по платежам графика
+//	        <Payment installment="1">
+//	          <Date>2026-02-10</Date>
+//	          <Amount>9100.00</Amount>
+//	        </Payment>
+//	      </Payments>
+//	      <Overdues>                                    текущие и погашенные просрочки
+//	        <Overdue installment="1" resolved="true">
+//	          <DueDate>2026-02-10</DueDate>
+//	          <Amount>9100.00</Amount>                  неоплаченная сумма (для погашенной - сумма платежа)
+//	          <DaysPastDue>4</DaysPastDue>
+//	          <ResolvedDate>2026-02-14</ResolvedDate>
+//	        </Overdue>
+//	      </Overdues>
+//	    </Credit>
+//	  </Subject>
+//	</CreditHistoryReport>
+//
+// Суммы указываются в рублях с двумя знаками после запятой, даты - в формате YYYY-MM-DD.
+package bureau
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+const FormatVersion = "1.0"
+
+const dateLayout = "2006-01-02"
+
+// Date - дата без времени в формате YYYY-MM-DD
+type Date time.Time
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(time.Time(d).Format(dateLayout)), nil
+}
+
+// Money - сумма в рублях с двумя знаками после запятой
+type Money float64
+
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%.2f", float64(m))), nil
+}
+
+type Report struct {
+	XMLName     xml.Name  `xml:"CreditHistoryReport"`
+	Version     string    `xml:"version,attr"`
+	Source      string    `xml:"source,attr"`
+	ReportDate  Date      `xml:"reportDate,attr"`
+	GeneratedAt time.Time `xml:"generatedAt,attr"`
+	Subjects    []Subject `xml:"Subject"`
+}
+
+type Subject struct {
+	ID       int64    `xml:"id,attr"`
+	FullName string   `xml:"FullName"`
+	Email    string   `xml:"Email,omitempty"`
+	Credits  []Credit `xml:"Credit"`
+}
+
+type Credit struct {
+	ID                   int64                `xml:"id,attr"`
+	ContractNumber       string               `xml:"contractNumber,attr"`
+	Status               models.CreditStatus  `xml:"Status"`
+	ProductID            int64                `xml:"ProductID,omitempty"`
+	Currency             string               `xml:"Currency"`
+	Amount               Money                `xml:"Amount"`
+	InterestRate         float64              `xml:"InterestRate"`
+	FullCostRate         float64              `xml:"FullCostRate"`
+	RepaymentType        models.RepaymentType `xml:"RepaymentType"`
+	Term                 int                  `xml:"Term"`
+	StartDate            Date                 `xml:"StartDate"`
+	EndDate              Date                 `xml:"EndDate"`
+	OutstandingPrincipal Money                `xml:"OutstandingPrincipal"`
+	OverdueAmount        Money                `xml:"OverdueAmount"`
+	MaxDaysPastDue       int                  `xml:"MaxDaysPastDue"`
+	Installments         []Installment        `xml:"Installments>Installment"`
+	Payments             []Payment            `xml:"Payments>Payment"`
+	Overdues             []Overdue            `xml:"Overdues>Overdue"`
+}
+
+type Installment struct {
+	Number     int                  `xml:"number,attr"`
+	DueDate    Date                 `xml:"DueDate"`
+	Amount     Money                `xml:"Amount"`
+	Principal  Money                `xml:"Principal"`
+	Interest   Money                `xml:"Interest"`
+	Status     models.PaymentStatus `xml:"Status"`
+	PaidAmount Money                `xml:"PaidAmount"`
+	PaidDate   *Date                `xml:"PaidDate,omitempty"`
+}
+
+type Payment struct {
+	Installment int   `xml:"installment,attr"`
+	Date        Date  `xml:"Date"`
+	Amount      Money `xml:"Amount"`
+}
+
+type Overdue struct {
+	Installment  int   `xml:"installment,attr"`
+	Resolved     bool  `xml:"resolved,attr"`
+	DueDate      Date  `xml:"DueDate"`
+	Amount       Money `xml:"Amount"`
+	DaysPastDue  int   `xml:"DaysPastDue"`
+	ResolvedDate *Date `xml:"ResolvedDate,omitempty"`
+}
+
+// ValidationError описывает незаполненное или некорректное обязательное поле записи
+type ValidationError struct {
+	SubjectID int64
+	CreditID  int64
+	Field     string
+	Message   string
+}
+
+func (e ValidationError) Error() string {
+	if e.CreditID != 0 {
+		return fmt.Sprintf("subject %d, credit %d: %s %s", e.SubjectID, e.CreditID, e.Field, e.Message)
+	}
+	return fmt.Sprintf("subject %d: %s %s", e.SubjectID, e.Field, e.Message)
+}
+
+// ValidateSubject проверяет обязательные поля заемщика
+func ValidateSubject(subject Subject) []ValidationError {
+	var errs []ValidationError
+
+	if subject.ID <= 0 {
+		errs = append(errs, ValidationError{SubjectID: subject.ID, Field: "id", Message: "is required"})
+	}
+	if subject.FullName == "" {
+		errs = append(errs, ValidationError{SubjectID: subject.ID, Field: "FullName", Message: "is required"})
+	}
+
+	return errs
+}
+
+// ValidateCredit проверяет обязательные поля кредита и его графика платежей
+func ValidateCredit(subjectID int64, credit Credit) []ValidationError {
+	var errs []ValidationError
+	fail := func(field, message string) {
+		errs = append(errs, ValidationError{SubjectID: subjectID, CreditID: credit.ID, Field: field, Message: message})
+	}
+
+	if credit.ID <= 0 {
+		fail("id", "is required")
+	}
+	if credit.ContractNumber == "" {
+		fail("contractNumber", "is required")
+	}
+	switch credit.Status {
+	case models.CreditStatusActive, models.CreditStatusOverdue, models.CreditStatusClosed:
+	default:
+		fail("Status", "must be ACTIVE, OVERDUE or CLOSED")
+	}
+	if credit.Amount <= 0 {
+		fail("Amount", "must be positive")
+	}
+	if !credit.RepaymentType.IsValid() {
+		fail("RepaymentType", "must be ANNUITY or DIFFERENTIATED")
+	}
+	if credit.InterestRate < 0 {
+		fail("InterestRate", "must not be negative")
+	}
+	if credit.Term <= 0 {
+		fail("Term", "must be positive")
+	}
+	if time.Time(credit.StartDate).IsZero() {
+		fail("StartDate", "is required")
+	}
+	if time.Time(credit.EndDate).IsZero() {
+		fail("EndDate", "is required")
+	}
+
+	for _, installment := range credit.Installments {
+		field := fmt.Sprintf("Installment[%d].", installment.Number)
+		if time.Time(installment.DueDate).IsZero() {
+			fail(field+"DueDate", "is required")
+		}
+		if installment.Amount <= 0 {
+			fail(field+"Amount", "must be positive")
+		}
+		if installment.Status == models.PaymentStatusPaid && installment.PaidDate == nil {
+			fail(field+"PaidDate", "is required for paid installments")
+		}
+	}
+
+	return errs
+}
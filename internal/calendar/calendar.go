@@ -0,0 +1,135 @@
+// Package calendar - производственный календарь для переноса дат платежей на рабочие дни.
+//
+// Календарь загружается из текстового файла, в котором перечислены нерабочие праздничные
+// дни и переносы выходных (формат описан в data/production_calendar.txt). Суббота
+// и воскресенье - выходные, если не объявлены рабочими днями. Для годов, которых нет
+// в файле, выходными считаются праздники по статье 112 ТК РФ без переносов.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Типы дней в файле календаря
+const (
+	dayHoliday = "H" // нерабочий праздничный день или перенесенный выходной
+	dayWorking = "W" // рабочий день, на который перенесен выходной
+)
+
+// Нерабочие праздничные дни по статье 112 ТК РФ (месяц, число)
+var statutoryHolidays = map[[2]int]bool{
+	{1, 1}: true, {1, 2}: true, {1, 3}: true, {1, 4}: true, {1, 5}: true, {1, 6}: true, {1, 7}: true, {1, 8}: true,
+	{2, 23}: true,
+	{3, 8}:  true,
+	{5, 1}:  true,
+	{5, 9}:  true,
+	{6, 12}: true,
+	{11, 4}: true,
+}
+
+// Calendar отвечает, является ли дата рабочим днем
+type Calendar struct {
+	holidays    map[string]bool
+	workingDays map[string]bool
+	years       map[int]bool // годы, описанные в файле
+}
+
+// New возвращает календарь без переносов: выходные и праздники по ТК РФ
+func New() *Calendar {
+	return &Calendar{
+		holidays:    make(map[string]bool),
+		workingDays: make(map[string]bool),
+		years:       make(map[int]bool),
+	}
+}
+
+// Load читает календарь из файла
+func Load(path string) (*Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(file)
+}
+
+// Parse читает календарь в формате "ДАТА ТИП [комментарий]". Пустые строки и строки,
+// начинающиеся с #, пропускаются.
+func Parse(r io.Reader) (*Calendar, error) {
+	calendar := New()
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("calendar line %d: expected date and day type", lineNumber)
+		}
+
+		date, err := time.Parse(dateLayout, fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("calendar line %d: invalid date %q", lineNumber, fields[0])
+		}
+
+		switch fields[1] {
+		case dayHoliday:
+			calendar.holidays[fields[0]] = true
+		case dayWorking:
+			calendar.workingDays[fields[0]] = true
+		default:
+			return nil, fmt.Errorf("calendar line %d: day type must be %s or %s", lineNumber, dayHoliday, dayWorking)
+		}
+
+		calendar.years[date.Year()] = true
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+// IsBusinessDay проверяет, является ли дата рабочим днем
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	key := t.Format(dateLayout)
+
+	if c.workingDays[key] {
+		return true
+	}
+	if c.holidays[key] {
+		return false
+	}
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	if !c.years[t.Year()] && statutoryHolidays[[2]int{int(t.Month()), t.Day()}] {
+		return false
+	}
+
+	return true
+}
+
+// NextBusinessDay возвращает t, если это рабочий день, иначе ближайший следующий рабочий
+// день с тем же временем
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	for !c.IsBusinessDay(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
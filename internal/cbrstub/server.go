@@ -0,0 +1,430 @@
+// Package cbrstub - заглушка веб-сервиса ЦБ РФ DailyInfo для разработки и тестов без сети.
+//
+// Server отвечает на SOAP-методы KeyRateXML (ключевая ставка за период, запись на каждый
+// рабочий день) и GetCursOnDateXML (курсы валют на дату) в формате ЦБ РФ. Данные
+// заданы по умолчанию и меняются методами SetKeyRates и SetCurrencyRates. Методом
+// SetMode метод переводится в режим ошибки: SOAP Fault, некорректный XML или HTTP 503.
+//
+// Заглушка подключается к CBRService через CBR_URL:
+//
+//	stub := cbrstub.New()
+//	server := httptest.NewServer(stub)
+//	cbrService := service.NewCBRService(cbrstub.NewKeyRateStore(), cbrstub.ClientConfig(server.URL))
+//
+// Для запуска отдельным процессом предназначена команда cbr-stub. Запущенной заглушкой
+// управляют HTTP-запросами к /stub/ (см. ServeHTTP).
+package cbrstub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beevik/etree"
+
+	"bank-service/internal/config"
+)
+
+// SOAP-методы DailyInfo, на которые отвечает заглушка
+const (
+	MethodKeyRate    = "KeyRateXML"
+	MethodCursOnDate = "GetCursOnDateXML"
+)
+
+const soapNamespace = "http://web.cbr.ru/"
+
+// Mode - режим ответа метода
+type Mode string
+
+const (
+	ModeOK          Mode = "OK"          // корректный ответ
+	ModeFault       Mode = "FAULT"       // SOAP Fault с HTTP 500
+	ModeMalformed   Mode = "MALFORMED"   // HTTP 200 с оборванным XML
+	ModeUnavailable Mode = "UNAVAILABLE" // HTTP 503 без тела
+)
+
+func (m Mode) IsValid() bool {
+	return m == ModeOK || m == ModeFault || m == ModeMalformed || m == ModeUnavailable
+}
+
+// KeyRate - изменение ключевой ставки: ставка Rate действует с даты Date
+type KeyRate struct {
+	Date time.Time `json:"date"`
+	Rate float64   `json:"rate"`
+}
+
+// CurrencyRate - курс валюты к рублю за Nominal единиц
+type CurrencyRate struct {
+	Code    string  `json:"code"`     // буквенный код, например USD
+	NumCode int     `json:"num_code"` // цифровой код, например 840
+	Name    string  `json:"name"`
+	Nominal int     `json:"nominal"`
+	Rate    float64 `json:"rate"`
+}
+
+// Выборка фактических изменений ключевой ставки
+var defaultKeyRates = []KeyRate{
+	{Date: date(2013, 9, 13), Rate: 5.5},
+	{Date: date(2022, 2, 28), Rate: 20},
+	{Date: date(2023, 12, 18), Rate: 16},
+	{Date: date(2024, 10, 28), Rate: 21},
+	{Date: date(2025, 6, 9), Rate: 20},
+	{Date: date(2025, 7, 28), Rate: 18},
+	{Date: date(2025, 9, 15), Rate: 17},
+	{Date: date(2025, 10, 27), Rate: 16.5},
+}
+
+var defaultCurrencyRates = []CurrencyRate{
+	{Code: "USD", NumCode: 840, Name: "Доллар США", Nominal: 1, Rate: 81.5},
+	{Code: "EUR", NumCode: 978, Name: "Евро", Nominal: 1, Rate: 94.7},
+	{Code: "CNY", NumCode: 156, Name: "Китайский юань", Nominal: 1, Rate: 11.4},
+}
+
+// modeState - режим метода. Если times больше нуля, режим действует для стольких
+// запросов, после чего метод снова отвечает корректно.
+type modeState struct {
+	mode  Mode
+	times int
+}
+
+type Server struct {
+	mu            sync.Mutex
+	keyRates      []KeyRate
+	currencyRates []CurrencyRate
+	modes         map[string]modeState
+	delay         time.Duration
+	calls         map[string]int
+}
+
+// New возвращает заглушку с данными по умолчанию
+func New() *Server {
+	s := &Server{}
+	s.Reset()
+	return s
+}
+
+// Reset возвращает данные по умолчанию, режим OK и обнуляет счетчики запросов
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keyRates = append([]KeyRate(nil), defaultKeyRates...)
+	s.currencyRates = append([]CurrencyRate(nil), defaultCurrencyRates...)
+	s.modes = make(map[string]modeState)
+	s.delay = 0
+	s.calls = make(map[string]int)
+}
+
+// SetKeyRates заменяет изменения ключевой ставки
+func (s *Server) SetKeyRates(rates ...KeyRate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keyRates = make([]KeyRate, 0, len(rates))
+	for _, rate := range rates {
+		s.keyRates = append(s.keyRates, KeyRate{Date: date(rate.Date.Year(), rate.Date.Month(), rate.Date.Day()), Rate: rate.Rate})
+	}
+	sort.Slice(s.keyRates, func(i, j int) bool { return s.keyRates[i].Date.Before(s.keyRates[j].Date) })
+}
+
+// SetCurrencyRates заменяет курсы валют, которые возвращаются на любую дату
+func (s *Server) SetCurrencyRates(rates ...CurrencyRate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.currencyRates = append([]CurrencyRate(nil), rates...)
+}
+
+// SetMode задает режим ответа метода на следующие times запросов (0 - до следующего вызова)
+func (s *Server) SetMode(method string, mode Mode, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.modes[method] = modeState{mode: mode, times: times}
+}
+
+// SetDelay задерживает каждый ответ, например для проверки таймаутов клиента
+func (s *Server) SetDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delay = delay
+}
+
+// Calls возвращает число запросов к методу
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[method]
+}
+
+// ServeHTTP отвечает на SOAP-запросы по любому пути, кроме /stub/. Управление заглушкой:
+//
+//	PUT  /stub/key-rates       [{"date": "2025-10-27T00:00:00Z", "rate": 16.5}, ...]
+//	PUT  /stub/currency-rates  [{"code": "USD", "num_code": 840, "name": "...", "nominal": 1, "rate": 81.5}, ...]
+//	POST /stub/mode            {"method": "KeyRateXML", "mode": "FAULT", "times": 2}
+//	POST /stub/reset
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/stub/") {
+		s.serveControl(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	doc := etree.NewDocument()
+	if _, err := doc.ReadFrom(r.Body); err != nil {
+		s.writeFault(w, "soap:Client", "invalid request XML")
+		return
+	}
+
+	body := doc.FindElement("//Body")
+	if body == nil || len(body.ChildElements()) == 0 {
+		s.writeFault(w, "soap:Client", "SOAP body is empty")
+		return
+	}
+	request := body.ChildElements()[0]
+	method := request.Tag
+
+	mode, delay := s.nextMode(method)
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	switch mode {
+	case ModeFault:
+		s.writeFault(w, "soap:Server", "Server was unable to process request")
+		return
+	case ModeMalformed:
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><%sResponse xmlns="%s"><%sResult><`, method, soapNamespace, method)
+		return
+	case ModeUnavailable:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	switch method {
+	case MethodKeyRate:
+		from, errFrom := parseSOAPDate(request, "fromDate")
+		to, errTo := parseSOAPDate(request, "ToDate")
+		if errFrom != nil || errTo != nil {
+			s.writeFault(w, "soap:Client", "fromDate and ToDate are required")
+			return
+		}
+		s.writeResult(w, method, s.keyRateResult(from, to))
+	case MethodCursOnDate:
+		onDate, err := parseSOAPDate(request, "On_date")
+		if err != nil {
+			s.writeFault(w, "soap:Client", "On_date is required")
+			return
+		}
+		s.writeResult(w, method, s.cursOnDateResult(onDate))
+	default:
+		s.writeFault(w, "soap:Client", fmt.Sprintf("method %s is not supported by the stub", method))
+	}
+}
+
+// nextMode учитывает запрос к методу и возвращает режим ответа на него
+func (s *Server) nextMode(method string) (Mode, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls[method]++
+
+	state, ok := s.modes[method]
+	if !ok {
+		return ModeOK, s.delay
+	}
+
+	if state.times > 0 {
+		state.times--
+		if state.times == 0 {
+			delete(s.modes, method)
+		} else {
+			s.modes[method] = state
+		}
+	}
+
+	return state.mode, s.delay
+}
+
+// keyRateResult формирует запись на каждый рабочий день периода от новых дат к старым
+func (s *Server) keyRateResult(from, to time.Time) *etree.Element {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := etree.NewElement("KeyRate")
+	result.CreateAttr("xmlns", "")
+
+	from = date(from.Year(), from.Month(), from.Day())
+	for day := date(to.Year(), to.Month(), to.Day()); !day.Before(from); day = day.AddDate(0, 0, -1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+
+		rate, ok := s.keyRateOn(day)
+		if !ok {
+			break
+		}
+
+		record := result.CreateElement("KR")
+		record.CreateElement("DT").SetText(day.Format("2006-01-02T15:04:05") + "+03:00")
+		record.CreateElement("Rate").SetText(fmt.Sprintf("%.2f", rate))
+	}
+
+	return result
+}
+
+func (s *Server) keyRateOn(day time.Time) (float64, bool) {
+	for i := len(s.keyRates) - 1; i >= 0; i-- {
+		if !s.keyRates[i].Date.After(day) {
+			return s.keyRates[i].Rate, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Server) cursOnDateResult(onDate time.Time) *etree.Element {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := etree.NewElement("ValuteData")
+	result.CreateAttr("xmlns", "")
+	result.CreateAttr("OnDate", onDate.Format("20060102"))
+
+	for _, rate := range s.currencyRates {
+		valute := result.CreateElement("ValuteCursOnDate")
+		valute.CreateElement("Vname").SetText(rate.Name)
+		valute.CreateElement("Vnom").SetText(fmt.Sprintf("%d", rate.Nominal))
+		valute.CreateElement("Vcurs").SetText(fmt.Sprintf("%.4f", rate.Rate))
+		valute.CreateElement("Vcode").SetText(fmt.Sprintf("%d", rate.NumCode))
+		valute.CreateElement("VchCode").SetText(rate.Code)
+		valute.CreateElement("VunitRate").SetText(fmt.Sprintf("%.4f", rate.Rate/float64(rate.Nominal)))
+	}
+
+	return result
+}
+
+func (s *Server) writeResult(w http.ResponseWriter, method string, result *etree.Element) {
+	doc, body := newEnvelope()
+	response := body.CreateElement(method + "Response")
+	response.CreateAttr("xmlns", soapNamespace)
+	response.CreateElement(method + "Result").AddChild(result)
+
+	writeEnvelope(w, http.StatusOK, doc)
+}
+
+func (s *Server) writeFault(w http.ResponseWriter, code, message string) {
+	doc, body := newEnvelope()
+	fault := body.CreateElement("soap:Fault")
+	fault.CreateElement("faultcode").SetText(code)
+	fault.CreateElement("faultstring").SetText(message)
+
+	writeEnvelope(w, http.StatusInternalServerError, doc)
+}
+
+func newEnvelope() (*etree.Document, *etree.Element) {
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="utf-8"`)
+
+	envelope := doc.CreateElement("soap:Envelope")
+	envelope.CreateAttr("xmlns:soap", "http://schemas.xmlsoap.org/soap/envelope/")
+	envelope.CreateAttr("xmlns:xsi", "http://www.w3.org/2001/XMLSchema-instance")
+	envelope.CreateAttr("xmlns:xsd", "http://www.w3.org/2001/XMLSchema")
+
+	return doc, envelope.CreateElement("soap:Body")
+}
+
+func writeEnvelope(w http.ResponseWriter, status int, doc *etree.Document) {
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(status)
+	doc.WriteTo(w)
+}
+
+func (s *Server) serveControl(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/stub/key-rates" && r.Method == http.MethodPut:
+		var rates []KeyRate
+		if err := json.NewDecoder(r.Body).Decode(&rates); err != nil {
+			http.Error(w, "invalid key rates", http.StatusBadRequest)
+			return
+		}
+		s.SetKeyRates(rates...)
+	case r.URL.Path == "/stub/currency-rates" && r.Method == http.MethodPut:
+		var rates []CurrencyRate
+		if err := json.NewDecoder(r.Body).Decode(&rates); err != nil {
+			http.Error(w, "invalid currency rates", http.StatusBadRequest)
+			return
+		}
+		for _, rate := range rates {
+			if rate.Nominal <= 0 {
+				http.Error(w, "nominal must be positive", http.StatusBadRequest)
+				return
+			}
+		}
+		s.SetCurrencyRates(rates...)
+	case r.URL.Path == "/stub/mode" && r.Method == http.MethodPost:
+		var request struct {
+			Method string `json:"method"`
+			Mode   Mode   `json:"mode"`
+			Times  int    `json:"times"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Method == "" || !request.Mode.IsValid() || request.Times < 0 {
+			http.Error(w, "method and mode (OK, FAULT, MALFORMED, UNAVAILABLE) are required", http.StatusBadRequest)
+			return
+		}
+		s.SetMode(request.Method, request.Mode, request.Times)
+	case r.URL.Path == "/stub/reset" && r.Method == http.MethodPost:
+		s.Reset()
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseSOAPDate читает дату из параметра метода в формате xsd:dateTime или xsd:date
+func parseSOAPDate(request *etree.Element, name string) (time.Time, error) {
+	element := request.SelectElement(name)
+	if element == nil {
+		return time.Time{}, fmt.Errorf("%s is missing", name)
+	}
+
+	text := strings.TrimSpace(element.Text())
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%s has invalid format", name)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// ClientConfig возвращает настройки CBRService для обращения к заглушке по адресу url:
+// короткие таймауты и паузы, без кэширования между вызовами
+func ClientConfig(url string) config.CBRConfig {
+	return config.CBRConfig{
+		URL:          url,
+		Timeout:      time.Second,
+		Retries:      2,
+		RetryBackoff: 10 * time.Millisecond,
+	}
+}
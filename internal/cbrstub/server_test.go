@@ -0,0 +1,148 @@
+package cbrstub_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bank-service/internal/cbrstub"
+	"bank-service/internal/service"
+)
+
+func newCBRService(t *testing.T) (*cbrstub.Server, service.CBRService) {
+	t.Helper()
+
+	stub := cbrstub.New()
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	return stub, service.NewCBRService(cbrstub.NewKeyRateStore(), cbrstub.ClientConfig(server.URL))
+}
+
+// weekdays считает рабочие дни (без учета праздников) с from по to включительно,
+// как заглушка формирует записи KeyRateXML
+func weekdays(from, to time.Time) int {
+	count := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
+
+func TestGetKeyRate(t *testing.T) {
+	stub, cbrService := newCBRService(t)
+
+	rate, err := cbrService.GetKeyRate(context.Background())
+	if err != nil {
+		t.Fatalf("GetKeyRate: %v", err)
+	}
+
+	if rate.Rate != 16.5 || rate.Stale {
+		t.Errorf("GetKeyRate = %.2f (stale %v), want 16.50 (stale false)", rate.Rate, rate.Stale)
+	}
+
+	if calls := stub.Calls(cbrstub.MethodKeyRate); calls != 1 {
+		t.Errorf("KeyRateXML calls = %d, want 1", calls)
+	}
+}
+
+func TestRefreshKeyRateHistory(t *testing.T) {
+	stub, cbrService := newCBRService(t)
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	want := weekdays(time.Date(2013, time.September, 13, 0, 0, 0, 0, time.Local), today)
+
+	count, err := cbrService.RefreshKeyRateHistory(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshKeyRateHistory: %v", err)
+	}
+	if count != want {
+		t.Errorf("first refresh loaded %d records, want %d", count, want)
+	}
+
+	// Повторно запрашивается только последняя сохраненная дата
+	count, err = cbrService.RefreshKeyRateHistory(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshKeyRateHistory: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("incremental refresh loaded %d records, want 1", count)
+	}
+
+	if calls := stub.Calls(cbrstub.MethodKeyRate); calls != 2 {
+		t.Errorf("KeyRateXML calls = %d, want 2", calls)
+	}
+}
+
+func TestGetKeyRateOnDate(t *testing.T) {
+	_, cbrService := newCBRService(t)
+
+	if _, err := cbrService.RefreshKeyRateHistory(context.Background()); err != nil {
+		t.Fatalf("RefreshKeyRateHistory: %v", err)
+	}
+
+	tests := []struct {
+		date time.Time
+		want float64
+	}{
+		{date: time.Date(2013, time.September, 13, 0, 0, 0, 0, time.Local), want: 5.5},
+		{date: time.Date(2022, time.February, 28, 0, 0, 0, 0, time.Local), want: 20},
+		{date: time.Date(2024, time.January, 10, 0, 0, 0, 0, time.Local), want: 16},
+		// Суббота: действует ставка последнего рабочего дня
+		{date: time.Date(2024, time.November, 2, 0, 0, 0, 0, time.Local), want: 21},
+		{date: time.Date(2025, time.October, 26, 0, 0, 0, 0, time.Local), want: 17},
+	}
+
+	for _, tt := range tests {
+		rate, err := cbrService.GetKeyRateOnDate(context.Background(), tt.date)
+		if err != nil {
+			t.Errorf("GetKeyRateOnDate(%s): %v", tt.date.Format("2006-01-02"), err)
+			continue
+		}
+		if rate.Rate != tt.want {
+			t.Errorf("GetKeyRateOnDate(%s) = %.2f, want %.2f", tt.date.Format("2006-01-02"), rate.Rate, tt.want)
+		}
+	}
+
+	if _, err := cbrService.GetKeyRateOnDate(context.Background(), time.Date(2013, time.September, 12, 0, 0, 0, 0, time.Local)); !errors.Is(err, service.ErrKeyRateUnavailable) {
+		t.Errorf("GetKeyRateOnDate before history start: err = %v, want %v", err, service.ErrKeyRateUnavailable)
+	}
+}
+
+func TestGetKeyRateFaultReturnsStaleRate(t *testing.T) {
+	stub, cbrService := newCBRService(t)
+
+	if _, err := cbrService.GetKeyRate(context.Background()); err != nil {
+		t.Fatalf("GetKeyRate: %v", err)
+	}
+
+	stub.SetMode(cbrstub.MethodKeyRate, cbrstub.ModeFault, 0)
+
+	rate, err := cbrService.GetKeyRate(context.Background())
+	if err != nil {
+		t.Fatalf("GetKeyRate with FAULT: %v", err)
+	}
+
+	if rate.Rate != 16.5 || !rate.Stale {
+		t.Errorf("GetKeyRate with FAULT = %.2f (stale %v), want 16.50 (stale true)", rate.Rate, rate.Stale)
+	}
+
+	// Один успешный запрос, затем первая попытка и два повтора
+	if calls := stub.Calls(cbrstub.MethodKeyRate); calls != 1+3 {
+		t.Errorf("KeyRateXML calls = %d, want 4", calls)
+	}
+}
+
+func TestGetKeyRateFaultWithoutCachedRate(t *testing.T) {
+	stub, cbrService := newCBRService(t)
+	stub.SetMode(cbrstub.MethodKeyRate, cbrstub.ModeFault, 0)
+
+	if _, err := cbrService.GetKeyRate(context.Background()); !errors.Is(err, service.ErrKeyRateUnavailable) {
+		t.Errorf("GetKeyRate with FAULT: err = %v, want %v", err, service.ErrKeyRateUnavailable)
+	}
+}
@@ -0,0 +1,106 @@
+package cbrstub
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"bank-service/internal/models"
+	"bank-service/internal/repository"
+)
+
+var _ repository.KeyRateRepository = (*KeyRateStore)(nil)
+
+// KeyRateStore хранит кэш и историю ключевой ставки в памяти. Реализует
+// repository.KeyRateRepository, чтобы CBRService работал без базы данных.
+type KeyRateStore struct {
+	mu      sync.Mutex
+	cached  *models.KeyRate
+	history map[time.Time]models.KeyRate
+}
+
+func NewKeyRateStore() *KeyRateStore {
+	return &KeyRateStore{history: make(map[time.Time]models.KeyRate)}
+}
+
+func (s *KeyRateStore) GetCached() (*models.KeyRate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached == nil {
+		return nil, nil
+	}
+
+	rate := *s.cached
+	return &rate, nil
+}
+
+func (s *KeyRateStore) SaveCached(rate models.KeyRate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cached = &rate
+	return nil
+}
+
+func (s *KeyRateStore) SaveHistory(rates []models.KeyRate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rate := range rates {
+		s.history[dayOf(rate.Date)] = rate
+	}
+	return nil
+}
+
+func (s *KeyRateStore) GetHistory(from, to time.Time) ([]models.KeyRate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rates []models.KeyRate
+	for day, rate := range s.history {
+		if !day.Before(dayOf(from)) && !day.After(dayOf(to)) {
+			rates = append(rates, rate)
+		}
+	}
+
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Date.Before(rates[j].Date) })
+	return rates, nil
+}
+
+func (s *KeyRateStore) GetOnDate(date time.Time) (*models.KeyRate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *models.KeyRate
+	for day, rate := range s.history {
+		if day.After(dayOf(date)) {
+			continue
+		}
+		if found == nil || day.After(dayOf(found.Date)) {
+			rate := rate
+			found = &rate
+		}
+	}
+
+	return found, nil
+}
+
+func (s *KeyRateStore) GetLastHistoryDate() (*time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var last *time.Time
+	for day := range s.history {
+		if last == nil || day.After(*last) {
+			day := day
+			last = &day
+		}
+	}
+
+	return last, nil
+}
+
+func dayOf(t time.Time) time.Time {
+	return date(t.Year(), t.Month(), t.Day())
+}
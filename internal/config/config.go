@@ -2,15 +2,24 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Security SecurityConfig
-	SMTP     SMTPConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Security   SecurityConfig
+	SMTP       SMTPConfig
+	OTP        OTPConfig
+	ISO8583    ISO8583Config
+	Credit     CreditConfig
+	CreditLine CreditLineConfig
+	Scoring    ScoringConfig
+	Bureau     BureauConfig
+	CBR        CBRConfig
 }
 
 type ServerConfig struct {
@@ -40,6 +49,68 @@ type SMTPConfig struct {
 	From     string
 }
 
+// OTPConfig задает подтверждение одноразовым кодом для крупных операций
+type OTPConfig struct {
+	Threshold   float64
+	TTL         time.Duration
+	MaxAttempts int
+}
+
+// ISO8583Config включает TCP-листенер для тестирования POS и эквайринга
+type ISO8583Config struct {
+	Enabled     bool
+	Port        string
+	IdleTimeout time.Duration
+	HoldTTL     time.Duration // срок, после которого незакрытый холд снимается (0 - не снимать)
+}
+
+// CreditConfig задает параметры обслуживания кредитов
+type CreditConfig struct {
+	PenaltyFineRate  float64 // разовый штраф, доля от просроченного платежа
+	PenaltyDailyRate float64 // пени в день, доля от просроченной суммы (0 - не начислять)
+	GracePeriodDays  int     // дней после даты платежа до перевода в OVERDUE
+	FullCostCap      float64 // предельная ПСК, % годовых (0 - без ограничения)
+	CalendarFile     string  // производственный календарь для переноса дат платежей на рабочие дни
+	FallbackKeyRate  float64 // ключевая ставка, если ЦБ РФ не ответил и сохраненной ставки нет
+
+	CollectionsFineRate          float64 // штраф при переходе в корзину 31-60 дней, доля от просроченной суммы
+	CollectionsPenaltyMultiplier float64 // множитель пени при просрочке более 30 дней
+}
+
+// CreditLineConfig задает параметры возобновляемых кредитных линий на счетах CREDIT
+type CreditLineConfig struct {
+	InterestRate       float64 // ставка на использованную часть лимита, % годовых
+	PaymentDays        int     // дней после закрытия выписки до даты минимального платежа
+	MinimumPaymentRate float64 // минимальный платеж, доля от задолженности
+	MinimumPayment     float64 // минимальный платеж в рублях, если доля от задолженности меньше
+	LateFee            float64 // штраф за пропуск минимального платежа
+}
+
+// ScoringConfig задает пороги скоринга кредитных заявок
+type ScoringConfig struct {
+	ApproveScore      int     // минимальный балл для автоматического одобрения
+	ReviewScore       int     // минимальный балл для ручной проверки
+	MaxDebtToIncome   float64 // предельная доля платежей по кредитам в доходе
+	MaxOverdueHistory int     // допустимое число просрочек в прошлом
+	ManualReview      bool    // отправлять пограничные заявки на ручную проверку
+}
+
+// BureauConfig задает выгрузку кредитных историй в бюро кредитных историй
+type BureauConfig struct {
+	ExportDir string // каталог, в который записываются файлы выгрузки
+	Source    string // код банка-источника в файле выгрузки
+}
+
+// CBRConfig задает обращение к веб-сервису ЦБ РФ за ключевой ставкой
+type CBRConfig struct {
+	URL             string        // адрес SOAP-сервиса DailyInfo
+	Timeout         time.Duration // таймаут одного запроса
+	Retries         int           // число повторных запросов после ошибки
+	RetryBackoff    time.Duration // пауза перед первым повтором, каждая следующая вдвое дольше
+	CacheTTL        time.Duration // срок, в течение которого ставка берется из кэша без запроса
+	FailureCooldown time.Duration // после неудачного обращения ЦБ РФ не запрашивается в течение этого времени
+}
+
 func LoadConfig() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		return nil, err
@@ -69,6 +140,54 @@ func LoadConfig() (*Config, error) {
 			Password: getEnv("SMTP_PASSWORD", ""),
 			From:     getEnv("SMTP_FROM", "test_bank@mail.ru"),
 		},
+		OTP: OTPConfig{
+			Threshold:   getEnvFloat("OTP_THRESHOLD", 100000),
+			TTL:         getEnvDuration("OTP_TTL", 5*time.Minute),
+			MaxAttempts: getEnvInt("OTP_MAX_ATTEMPTS", 3),
+		},
+		ISO8583: ISO8583Config{
+			Enabled:     getEnvBool("ISO8583_ENABLED", false),
+			Port:        getEnv("ISO8583_PORT", "8583"),
+			IdleTimeout: getEnvDuration("ISO8583_IDLE_TIMEOUT", 5*time.Minute),
+			HoldTTL:     getEnvDuration("ISO8583_HOLD_TTL", 7*24*time.Hour),
+		},
+		Credit: CreditConfig{
+			PenaltyFineRate:  getEnvFloat("CREDIT_PENALTY_FINE_RATE", 0.10),
+			PenaltyDailyRate: getEnvFloat("CREDIT_PENALTY_DAILY_RATE", 0),
+			GracePeriodDays:  getEnvInt("CREDIT_GRACE_PERIOD_DAYS", 3),
+			FullCostCap:      getEnvFloat("CREDIT_FULL_COST_CAP", 292),
+			CalendarFile:     getEnv("CREDIT_CALENDAR_FILE", "data/production_calendar.txt"),
+			FallbackKeyRate:  getEnvFloat("CREDIT_FALLBACK_KEY_RATE", 7.5),
+
+			CollectionsFineRate:          getEnvFloat("CREDIT_COLLECTIONS_FINE_RATE", 0.05),
+			CollectionsPenaltyMultiplier: getEnvFloat("CREDIT_COLLECTIONS_PENALTY_MULTIPLIER", 2),
+		},
+		CreditLine: CreditLineConfig{
+			InterestRate:       getEnvFloat("CREDIT_LINE_INTEREST_RATE", 29.9),
+			PaymentDays:        getEnvInt("CREDIT_LINE_PAYMENT_DAYS", 20),
+			MinimumPaymentRate: getEnvFloat("CREDIT_LINE_MIN_PAYMENT_RATE", 0.05),
+			MinimumPayment:     getEnvFloat("CREDIT_LINE_MIN_PAYMENT", 500),
+			LateFee:            getEnvFloat("CREDIT_LINE_LATE_FEE", 500),
+		},
+		Scoring: ScoringConfig{
+			ApproveScore:      getEnvInt("SCORING_APPROVE_SCORE", 70),
+			ReviewScore:       getEnvInt("SCORING_REVIEW_SCORE", 50),
+			MaxDebtToIncome:   getEnvFloat("SCORING_MAX_DTI", 0.5),
+			MaxOverdueHistory: getEnvInt("SCORING_MAX_OVERDUE_HISTORY", 3),
+			ManualReview:      getEnvBool("SCORING_MANUAL_REVIEW", true),
+		},
+		Bureau: BureauConfig{
+			ExportDir: getEnv("BUREAU_EXPORT_DIR", "exports/bureau"),
+			Source:    getEnv("BUREAU_SOURCE", "BANK"),
+		},
+		CBR: CBRConfig{
+			URL:             getEnv("CBR_URL", "https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx"),
+			Timeout:         getEnvDuration("CBR_TIMEOUT", 10*time.Second),
+			Retries:         getEnvInt("CBR_RETRIES", 2),
+			RetryBackoff:    getEnvDuration("CBR_RETRY_BACKOFF", 500*time.Millisecond),
+			CacheTTL:        getEnvDuration("CBR_CACHE_TTL", time.Hour),
+			FailureCooldown: getEnvDuration("CBR_FAILURE_COOLDOWN", time.Minute),
+		},
 	}, nil
 }
 
@@ -78,3 +197,39 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
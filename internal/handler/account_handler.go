@@ -135,6 +135,10 @@ func (h *Handler) WithdrawFromAccount(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.services.Account.Withdraw(input, userID); err != nil {
+		if h.confirmationRequired(w, err) {
+			return
+		}
+
 		h.logger.Infof("Failed to withdraw: %v", err)
 
 		switch err {
@@ -170,6 +174,10 @@ func (h *Handler) TransferFunds(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.services.Account.Transfer(input, userID); err != nil {
+		if h.confirmationRequired(w, err) {
+			return
+		}
+
 		h.logger.Infof("Failed to transfer: %v", err)
 
 		switch err {
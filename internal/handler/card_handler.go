@@ -148,6 +148,10 @@ func (h *Handler) ProcessCardPayment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.services.Card.ProcessPayment(input, userID); err != nil {
+		if h.confirmationRequired(w, err) {
+			return
+		}
+
 		h.logger.Infof("Failed to process payment: %v", err)
 
 		switch err {
@@ -157,6 +161,8 @@ func (h *Handler) ProcessCardPayment(w http.ResponseWriter, r *http.Request) {
 			h.errorResponse(w, http.StatusForbidden, "Access to this card is denied")
 		case service.ErrCardInactive:
 			h.errorResponse(w, http.StatusBadRequest, "Card is inactive")
+		case service.ErrCardBlocked:
+			h.errorResponse(w, http.StatusConflict, err.Error())
 		case service.ErrInsufficientFunds:
 			h.errorResponse(w, http.StatusBadRequest, "Insufficient funds")
 		default:
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -29,6 +31,11 @@ func (h *Handler) ApplyForCredit(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		h.logger.Infof("Failed to apply for credit: %v", err)
 
+		if errors.Is(err, service.ErrCreditProductNotEligible) {
+			h.errorResponse(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
 		switch err {
 		case service.ErrAccountNotFound:
 			h.errorResponse(w, http.StatusNotFound, "Account not found")
@@ -36,18 +43,55 @@ func (h *Handler) ApplyForCredit(w http.ResponseWriter, r *http.Request) {
 			h.errorResponse(w, http.StatusForbidden, "Access to this account is denied")
 		case service.ErrInvalidCreditAmount:
 			h.errorResponse(w, http.StatusBadRequest, "Credit amount must be positive")
-		case service.ErrInvalidCreditTerm:
-			h.errorResponse(w, http.StatusBadRequest, "Credit term must be between 3 and 60 months")
+		case service.ErrInvalidCreditTerm, service.ErrCreditAmountOutOfRange:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		case service.ErrCreditProductNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Credit product not found")
+		case service.ErrInvalidDeclaredIncome:
+			h.errorResponse(w, http.StatusBadRequest, "Declared income cannot be negative")
+		case service.ErrInvalidRepaymentType:
+			h.errorResponse(w, http.StatusBadRequest, "Repayment type must be ANNUITY or DIFFERENTIATED")
+		case service.ErrCreditApplicationsFrozen:
+			h.errorResponse(w, http.StatusForbidden, err.Error())
 		default:
 			h.errorResponse(w, http.StatusInternalServerError, "Failed to apply for credit")
 		}
 		return
 	}
 
-	h.logger.Infof("Credit applied successfully for user %d, amount %.2f", userID, input.Amount)
+	h.logger.Infof("Credit application from user %d, amount %.2f: %s (score %d)", userID, input.Amount, credit.Status, credit.Score)
 	h.successResponse(w, http.StatusCreated, credit)
 }
 
+func (h *Handler) QuoteCredit(w http.ResponseWriter, r *http.Request) {
+	var input models.CreditQuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	quotes, err := h.services.Credit.Quote(input)
+	if err != nil {
+		h.logger.Infof("Failed to quote credit: %v", err)
+
+		switch err {
+		case service.ErrInvalidCreditAmount:
+			h.errorResponse(w, http.StatusBadRequest, "Credit amount must be positive")
+		case service.ErrInvalidCreditTerm, service.ErrCreditAmountOutOfRange:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		case service.ErrCreditProductNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Credit product not found")
+		case service.ErrInvalidRepaymentType, service.ErrInvalidQuoteRequest:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to quote credit")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, quotes)
+}
+
 func (h *Handler) GetCredit(w http.ResponseWriter, r *http.Request) {
 	userID, err := middleware.GetUserID(r.Context())
 	if err != nil {
@@ -128,3 +172,303 @@ func (h *Handler) GetCreditSchedule(w http.ResponseWriter, r *http.Request) {
 
 	h.successResponse(w, http.StatusOK, schedule)
 }
+
+// GetCreditInterest возвращает проценты к уплате на дату из параметра date (YYYY-MM-DD),
+// по умолчанию - на текущий момент
+func (h *Handler) GetCreditInterest(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	creditID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid credit ID")
+		return
+	}
+
+	asOf := time.Now()
+	if dateParam := r.URL.Query().Get("date"); dateParam != "" {
+		date, err := time.ParseInLocation("2006-01-02", dateParam, time.Local)
+		if err != nil {
+			h.errorResponse(w, http.StatusBadRequest, "Invalid date, expected YYYY-MM-DD")
+			return
+		}
+		// Проценты считаются по конец указанного дня
+		asOf = date.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	interest, err := h.services.Credit.GetInterestOwed(creditID, userID, asOf)
+	if err != nil {
+		h.logger.Infof("Failed to get credit interest: %v", err)
+
+		switch err {
+		case service.ErrCreditNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Credit not found")
+		case service.ErrCreditAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this credit is denied")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to get credit interest")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, interest)
+}
+
+func (h *Handler) GetCreditStatusHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	creditID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid credit ID")
+		return
+	}
+
+	history, err := h.services.Credit.GetStatusHistory(creditID, userID)
+	if err != nil {
+		h.logger.Infof("Failed to get credit status history: %v", err)
+
+		switch err {
+		case service.ErrCreditNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Credit not found")
+		case service.ErrCreditAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this credit is denied")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to get credit status history")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, history)
+}
+
+func (h *Handler) GetCreditRateHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	creditID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid credit ID")
+		return
+	}
+
+	history, err := h.services.Credit.GetRateHistory(creditID, userID)
+	if err != nil {
+		h.logger.Infof("Failed to get credit rate history: %v", err)
+
+		switch err {
+		case service.ErrCreditNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Credit not found")
+		case service.ErrCreditAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this credit is denied")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to get credit rate history")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, history)
+}
+
+func (h *Handler) RepayCreditEarly(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	creditID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid credit ID")
+		return
+	}
+
+	var input models.EarlyRepaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	credit, err := h.services.Credit.EarlyRepay(creditID, userID, input)
+	if err != nil {
+		h.logger.Infof("Failed to repay credit early: %v", err)
+
+		switch err {
+		case service.ErrCreditNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Credit not found")
+		case service.ErrCreditAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this credit is denied")
+		case service.ErrAccountNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Account not found")
+		case service.ErrInvalidAmount:
+			h.errorResponse(w, http.StatusBadRequest, "Amount must be positive")
+		case service.ErrInvalidRepaymentMode, service.ErrCreditHasOverdue, service.ErrCreditNotRepayable:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		case service.ErrInsufficientFunds, models.ErrInsufficientFunds:
+			h.errorResponse(w, http.StatusBadRequest, "Insufficient funds")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to repay credit")
+		}
+		return
+	}
+
+	h.logger.Infof("Early repayment for credit %d by user %d, status %s", creditID, userID, credit.Status)
+	h.successResponse(w, http.StatusOK, credit)
+}
+
+func (h *Handler) RestructureCredit(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	creditID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid credit ID")
+		return
+	}
+
+	var input models.CreditRestructuringRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	credit, err := h.services.Credit.Restructure(creditID, userID, input)
+	if err != nil {
+		h.logger.Infof("Failed to restructure credit: %v", err)
+
+		switch err {
+		case service.ErrCreditNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Credit not found")
+		case service.ErrCreditAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this credit is denied")
+		case service.ErrInvalidRestructuring, service.ErrInvalidCreditTerm:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		case service.ErrCreditHasOverdue, service.ErrCreditNotRepayable, service.ErrPaymentHolidayLimitExceeded:
+			h.errorResponse(w, http.StatusConflict, err.Error())
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to restructure credit")
+		}
+		return
+	}
+
+	h.logger.Infof("Credit %d restructured (%s) by user %d", creditID, input.Type, userID)
+	h.successResponse(w, http.StatusOK, credit)
+}
+
+func (h *Handler) GetCreditRestructurings(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	creditID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid credit ID")
+		return
+	}
+
+	restructurings, err := h.services.Credit.GetRestructurings(creditID, userID)
+	if err != nil {
+		h.logger.Infof("Failed to get credit restructurings: %v", err)
+
+		switch err {
+		case service.ErrCreditNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Credit not found")
+		case service.ErrCreditAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this credit is denied")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to get credit restructurings")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, restructurings)
+}
+
+func (h *Handler) GetCreditReviewQueue(w http.ResponseWriter, r *http.Request) {
+	credits, err := h.services.Credit.GetReviewQueue()
+	if err != nil {
+		h.logger.Errorf("Failed to get credit review queue: %v", err)
+		h.errorResponse(w, http.StatusInternalServerError, "Failed to get credit applications")
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, credits)
+}
+
+func (h *Handler) GetCollectionQueue(w http.ResponseWriter, r *http.Request) {
+	bucket := models.CollectionBucket(r.URL.Query().Get("bucket"))
+
+	summaries, err := h.services.Credit.GetCollectionQueue(bucket)
+	if err != nil {
+		h.logger.Infof("Failed to get collection queue: %v", err)
+
+		switch err {
+		case service.ErrInvalidCollectionBucket:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to get delinquent credits")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, summaries)
+}
+
+func (h *Handler) DecideCredit(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	creditID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid credit ID")
+		return
+	}
+
+	var input models.CreditDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	credit, err := h.services.Credit.Decide(creditID, input)
+	if err != nil {
+		h.logger.Infof("Failed to decide credit application: %v", err)
+
+		switch err {
+		case service.ErrCreditNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Credit not found")
+		case service.ErrAccountNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Account not found")
+		case service.ErrInvalidCreditDecision:
+			h.errorResponse(w, http.StatusBadRequest, "Decision must be APPROVED or REJECTED")
+		case service.ErrCreditNotPending:
+			h.errorResponse(w, http.StatusConflict, "Credit application is not awaiting review")
+		case service.ErrFullCostExceedsCap:
+			h.errorResponse(w, http.StatusUnprocessableEntity, "Full cost of credit exceeds the regulatory cap")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to decide credit application")
+		}
+		return
+	}
+
+	h.logger.Infof("Credit application %d decided: %s", creditID, credit.Status)
+	h.successResponse(w, http.StatusOK, credit)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"bank-service/internal/middleware"
+	"bank-service/internal/models"
+	"bank-service/internal/service"
+)
+
+func (h *Handler) GetCreditLineStatements(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	accountID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+
+	statements, err := h.services.CreditLine.GetStatements(accountID, userID)
+	if err != nil {
+		h.logger.Infof("Failed to get credit line statements: %v", err)
+
+		switch err {
+		case service.ErrAccountNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Account not found")
+		case service.ErrAccountAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this account is denied")
+		case service.ErrNotCreditAccount:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to get credit line statements")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, statements)
+}
+
+func (h *Handler) SetCreditLimit(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	accountID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid account ID")
+		return
+	}
+
+	var request models.CreditLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	account, err := h.services.CreditLine.SetCreditLimit(accountID, request.CreditLimit)
+	if err != nil {
+		h.logger.Infof("Failed to set credit limit: %v", err)
+
+		switch err {
+		case service.ErrAccountNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Account not found")
+		case service.ErrNotCreditAccount, service.ErrInvalidCreditLimit:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to set credit limit")
+		}
+		return
+	}
+
+	h.logger.Infof("Credit limit of account %s set to %.2f", account.Number, account.CreditLimit)
+	h.successResponse(w, http.StatusOK, account)
+}
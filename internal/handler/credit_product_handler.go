@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"bank-service/internal/models"
+	"bank-service/internal/service"
+)
+
+func (h *Handler) GetCreditProducts(w http.ResponseWriter, r *http.Request) {
+	products, err := h.services.CreditProduct.GetActive()
+	if err != nil {
+		h.logger.Errorf("Failed to get credit products: %v", err)
+		h.errorResponse(w, http.StatusInternalServerError, "Failed to get credit products")
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, products)
+}
+
+func (h *Handler) GetCreditProductCatalog(w http.ResponseWriter, r *http.Request) {
+	products, err := h.services.CreditProduct.GetAll()
+	if err != nil {
+		h.logger.Errorf("Failed to get credit product catalog: %v", err)
+		h.errorResponse(w, http.StatusInternalServerError, "Failed to get credit products")
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, products)
+}
+
+func (h *Handler) CreateCreditProduct(w http.ResponseWriter, r *http.Request) {
+	var input models.CreditProductInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	product, err := h.services.CreditProduct.Create(input)
+	if err != nil {
+		h.creditProductError(w, "Failed to create credit product", err)
+		return
+	}
+
+	h.logger.Infof("Credit product %s created", product.Code)
+	h.successResponse(w, http.StatusCreated, product)
+}
+
+func (h *Handler) UpdateCreditProduct(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	productID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid credit product ID")
+		return
+	}
+
+	var input models.CreditProductInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	product, err := h.services.CreditProduct.Update(productID, input)
+	if err != nil {
+		h.creditProductError(w, "Failed to update credit product", err)
+		return
+	}
+
+	h.logger.Infof("Credit product %s updated", product.Code)
+	h.successResponse(w, http.StatusOK, product)
+}
+
+func (h *Handler) creditProductError(w http.ResponseWriter, message string, err error) {
+	h.logger.Infof("%s: %v", message, err)
+
+	switch {
+	case errors.Is(err, service.ErrCreditProductNotFound):
+		h.errorResponse(w, http.StatusNotFound, "Credit product not found")
+	case errors.Is(err, service.ErrInvalidCreditProduct):
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		h.errorResponse(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"bank-service/internal/middleware"
+	"bank-service/internal/models"
+	"bank-service/internal/service"
+)
+
+func (h *Handler) OpenDispute(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input models.DisputeCreation
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	dispute, err := h.services.Dispute.Open(userID, input)
+	if err != nil {
+		h.logger.Infof("Failed to open dispute: %v", err)
+
+		switch err {
+		case service.ErrTransactionNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Transaction not found")
+		case service.ErrAccountAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this transaction is denied")
+		case service.ErrInvalidDisputeReason, service.ErrTransactionNotDisputable, service.ErrDisputeWindowExpired:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		case service.ErrDisputeAlreadyExists:
+			h.errorResponse(w, http.StatusConflict, "Transaction is already disputed")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to open dispute")
+		}
+		return
+	}
+
+	h.logger.Infof("Dispute %d opened by user %d for transaction %d", dispute.ID, userID, input.TransactionID)
+	h.successResponse(w, http.StatusCreated, dispute)
+}
+
+func (h *Handler) GetDispute(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	disputeID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid dispute ID")
+		return
+	}
+
+	dispute, err := h.services.Dispute.GetByID(disputeID, userID)
+	if err != nil {
+		h.logger.Infof("Failed to get dispute: %v", err)
+
+		switch err {
+		case service.ErrDisputeNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Dispute not found")
+		case service.ErrDisputeAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this dispute is denied")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to get dispute")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, dispute)
+}
+
+func (h *Handler) GetUserDisputes(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	disputes, err := h.services.Dispute.GetByUserID(userID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user disputes: %v", err)
+		h.errorResponse(w, http.StatusInternalServerError, "Failed to get disputes")
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, disputes)
+}
+
+func (h *Handler) GetDisputeQueue(w http.ResponseWriter, r *http.Request) {
+	status := models.DisputeStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = models.DisputeStatusOpened // Значение по умолчанию
+	}
+
+	disputes, err := h.services.Dispute.GetByStatus(status)
+	if err != nil {
+		h.logger.Errorf("Failed to get dispute queue: %v", err)
+		h.errorResponse(w, http.StatusInternalServerError, "Failed to get disputes")
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, disputes)
+}
+
+func (h *Handler) ReviewDispute(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	disputeID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid dispute ID")
+		return
+	}
+
+	dispute, err := h.services.Dispute.StartReview(disputeID)
+	if err != nil {
+		h.disputeOperatorError(w, "Failed to start dispute review", err)
+		return
+	}
+
+	h.logger.Infof("Dispute %d moved to review", disputeID)
+	h.successResponse(w, http.StatusOK, dispute)
+}
+
+func (h *Handler) ResolveDispute(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	disputeID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid dispute ID")
+		return
+	}
+
+	var input models.DisputeResolution
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	dispute, err := h.services.Dispute.Resolve(disputeID, input)
+	if err != nil {
+		h.disputeOperatorError(w, "Failed to resolve dispute", err)
+		return
+	}
+
+	h.logger.Infof("Dispute %d resolved: %s", disputeID, dispute.Status)
+	h.successResponse(w, http.StatusOK, dispute)
+}
+
+func (h *Handler) FinalizeDispute(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	disputeID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid dispute ID")
+		return
+	}
+
+	dispute, err := h.services.Dispute.FinalizeReversal(disputeID)
+	if err != nil {
+		h.disputeOperatorError(w, "Failed to finalize dispute", err)
+		return
+	}
+
+	h.logger.Infof("Dispute %d reversal finalized", disputeID)
+	h.successResponse(w, http.StatusOK, dispute)
+}
+
+func (h *Handler) disputeOperatorError(w http.ResponseWriter, message string, err error) {
+	h.logger.Infof("%s: %v", message, err)
+
+	switch err {
+	case service.ErrDisputeNotFound:
+		h.errorResponse(w, http.StatusNotFound, "Dispute not found")
+	case service.ErrAccountNotFound:
+		h.errorResponse(w, http.StatusNotFound, "Account not found")
+	case service.ErrInvalidDisputeDecision:
+		h.errorResponse(w, http.StatusBadRequest, "Decision must be ACCEPTED or REJECTED")
+	case service.ErrInvalidDisputeTransition:
+		h.errorResponse(w, http.StatusConflict, "Dispute cannot be moved to this status")
+	default:
+		h.errorResponse(w, http.StatusInternalServerError, message)
+	}
+}
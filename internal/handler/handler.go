@@ -35,6 +35,9 @@ func (h *Handler) registerPublicRoutes(router *mux.Router) {
 }
 
 func (h *Handler) registerProtectedRoutes(router *mux.Router) {
+	router.HandleFunc("/profile", h.GetProfile).Methods("GET")
+	router.HandleFunc("/profile/income", h.UpdateDeclaredIncome).Methods("PUT")
+
 	router.HandleFunc("/accounts", h.CreateAccount).Methods("POST")
 	router.HandleFunc("/accounts", h.GetUserAccounts).Methods("GET")
 	router.HandleFunc("/accounts/{id:[0-9]+}", h.GetAccount).Methods("GET")
@@ -52,12 +55,50 @@ func (h *Handler) registerProtectedRoutes(router *mux.Router) {
 
 	router.HandleFunc("/credits", h.ApplyForCredit).Methods("POST")
 	router.HandleFunc("/credits", h.GetUserCredits).Methods("GET")
+	router.HandleFunc("/credits/quote", h.QuoteCredit).Methods("POST")
+	router.HandleFunc("/credit-products", h.GetCreditProducts).Methods("GET")
 	router.HandleFunc("/credits/{id:[0-9]+}", h.GetCredit).Methods("GET")
 	router.HandleFunc("/credits/{id:[0-9]+}/schedule", h.GetCreditSchedule).Methods("GET")
+	router.HandleFunc("/credits/{id:[0-9]+}/history", h.GetCreditStatusHistory).Methods("GET")
+	router.HandleFunc("/credits/{id:[0-9]+}/rates", h.GetCreditRateHistory).Methods("GET")
+	router.HandleFunc("/credits/{id:[0-9]+}/interest", h.GetCreditInterest).Methods("GET")
+	router.HandleFunc("/credits/{id:[0-9]+}/repay", h.RepayCreditEarly).Methods("POST")
+	router.HandleFunc("/credits/{id:[0-9]+}/restructure", h.RestructureCredit).Methods("POST")
+	router.HandleFunc("/credits/{id:[0-9]+}/restructurings", h.GetCreditRestructurings).Methods("GET")
+
+	router.HandleFunc("/rates/key", h.GetKeyRates).Methods("GET")
 
 	router.HandleFunc("/transactions", h.GetUserTransactions).Methods("GET")
 	router.HandleFunc("/accounts/{id:[0-9]+}/transactions", h.GetAccountTransactions).Methods("GET")
+	router.HandleFunc("/accounts/{id:[0-9]+}/statements", h.GetCreditLineStatements).Methods("GET")
 
 	router.HandleFunc("/analytics/transactions", h.GetTransactionAnalytics).Methods("GET")
 	router.HandleFunc("/analytics/credits", h.GetCreditAnalytics).Methods("GET")
+
+	router.HandleFunc("/operations/{id:[0-9]+}/confirm", h.ConfirmOperation).Methods("POST")
+
+	router.HandleFunc("/disputes", h.OpenDispute).Methods("POST")
+	router.HandleFunc("/disputes", h.GetUserDisputes).Methods("GET")
+	router.HandleFunc("/disputes/{id:[0-9]+}", h.GetDispute).Methods("GET")
+
+	operator := router.PathPrefix("/operator").Subrouter()
+	operator.Use(middleware.OperatorMiddleware(h.services.User))
+	h.registerOperatorRoutes(operator)
+}
+
+func (h *Handler) registerOperatorRoutes(router *mux.Router) {
+	router.HandleFunc("/disputes", h.GetDisputeQueue).Methods("GET")
+	router.HandleFunc("/disputes/{id:[0-9]+}/review", h.ReviewDispute).Methods("POST")
+	router.HandleFunc("/disputes/{id:[0-9]+}/resolve", h.ResolveDispute).Methods("POST")
+	router.HandleFunc("/disputes/{id:[0-9]+}/finalize", h.FinalizeDispute).Methods("POST")
+
+	router.HandleFunc("/credit-products", h.GetCreditProductCatalog).Methods("GET")
+	router.HandleFunc("/credit-products", h.CreateCreditProduct).Methods("POST")
+	router.HandleFunc("/credit-products/{id:[0-9]+}", h.UpdateCreditProduct).Methods("PUT")
+
+	router.HandleFunc("/accounts/{id:[0-9]+}/credit-limit", h.SetCreditLimit).Methods("PUT")
+
+	router.HandleFunc("/credits/review", h.GetCreditReviewQueue).Methods("GET")
+	router.HandleFunc("/credits/{id:[0-9]+}/decision", h.DecideCredit).Methods("POST")
+	router.HandleFunc("/collections", h.GetCollectionQueue).Methods("GET")
 }
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"bank-service/internal/middleware"
+	"bank-service/internal/models"
+	"bank-service/internal/service"
+)
+
+func (h *Handler) ConfirmOperation(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	operationID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid operation ID")
+		return
+	}
+
+	var input models.OperationConfirmation
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	operation, err := h.services.Operation.Confirm(operationID, userID, input.Code)
+	if err != nil {
+		h.logger.Infof("Failed to confirm operation: %v", err)
+
+		switch err {
+		case service.ErrOperationNotFound:
+			h.errorResponse(w, http.StatusNotFound, "Operation not found")
+		case service.ErrOperationAccessDenied:
+			h.errorResponse(w, http.StatusForbidden, "Access to this operation is denied")
+		case service.ErrInvalidOperationCode:
+			h.errorResponse(w, http.StatusBadRequest, "Invalid confirmation code")
+		case service.ErrOperationExpired:
+			h.errorResponse(w, http.StatusGone, "Confirmation code has expired")
+		case service.ErrOperationLocked:
+			h.errorResponse(w, http.StatusTooManyRequests, "Too many invalid confirmation attempts")
+		case service.ErrOperationNotPending:
+			h.errorResponse(w, http.StatusConflict, "Operation is not awaiting confirmation")
+		case service.ErrInsufficientFunds, models.ErrInsufficientFunds:
+			h.errorResponse(w, http.StatusBadRequest, "Insufficient funds")
+		case service.ErrCardInactive:
+			h.errorResponse(w, http.StatusBadRequest, "Card is inactive")
+		case service.ErrCardBlocked:
+			h.errorResponse(w, http.StatusConflict, err.Error())
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to confirm operation")
+		}
+		return
+	}
+
+	h.logger.Infof("Operation %d confirmed by user %d", operationID, userID)
+	h.successResponse(w, http.StatusOK, operation)
+}
+
+// confirmationRequired отвечает 202 Accepted, если операция отложена до ввода кода
+func (h *Handler) confirmationRequired(w http.ResponseWriter, err error) bool {
+	var confirmation *service.ConfirmationRequiredError
+	if !errors.As(err, &confirmation) {
+		return false
+	}
+
+	h.logger.Infof("Operation %d awaits OTP confirmation", confirmation.Operation.ID)
+	h.successResponse(w, http.StatusAccepted, confirmation.Operation)
+	return true
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"bank-service/internal/middleware"
+	"bank-service/internal/models"
+	"bank-service/internal/service"
+)
+
+func (h *Handler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	user, err := h.services.User.GetByID(userID)
+	if err != nil {
+		h.logger.Infof("Failed to get profile: %v", err)
+
+		switch err {
+		case service.ErrUserNotFound:
+			h.errorResponse(w, http.StatusNotFound, "User not found")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to get profile")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, user)
+}
+
+func (h *Handler) UpdateDeclaredIncome(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input models.DeclaredIncomeRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, err := h.services.User.UpdateDeclaredIncome(userID, input.DeclaredIncome)
+	if err != nil {
+		h.logger.Infof("Failed to update declared income: %v", err)
+
+		switch err {
+		case service.ErrInvalidDeclaredIncome:
+			h.errorResponse(w, http.StatusBadRequest, "Declared income cannot be negative")
+		case service.ErrUserNotFound:
+			h.errorResponse(w, http.StatusNotFound, "User not found")
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to update declared income")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, user)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"bank-service/internal/service"
+)
+
+// GetKeyRates возвращает историю ключевой ставки ЦБ РФ за период from - to (YYYY-MM-DD).
+// По умолчанию to - текущий день, from - месяц до to.
+func (h *Handler) GetKeyRates(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if toParam := r.URL.Query().Get("to"); toParam != "" {
+		date, err := time.ParseInLocation("2006-01-02", toParam, time.Local)
+		if err != nil {
+			h.errorResponse(w, http.StatusBadRequest, "Invalid to date, expected YYYY-MM-DD")
+			return
+		}
+		to = date
+	}
+
+	from := to.AddDate(0, -1, 0)
+	if fromParam := r.URL.Query().Get("from"); fromParam != "" {
+		date, err := time.ParseInLocation("2006-01-02", fromParam, time.Local)
+		if err != nil {
+			h.errorResponse(w, http.StatusBadRequest, "Invalid from date, expected YYYY-MM-DD")
+			return
+		}
+		from = date
+	}
+
+	rates, err := h.services.CBR.GetKeyRateHistory(from, to)
+	if err != nil {
+		h.logger.Infof("Failed to get key rate history: %v", err)
+
+		switch err {
+		case service.ErrInvalidDateRange:
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			h.errorResponse(w, http.StatusInternalServerError, "Failed to get key rate history")
+		}
+		return
+	}
+
+	h.successResponse(w, http.StatusOK, rates)
+}
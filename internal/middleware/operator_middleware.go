@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"bank-service/internal/service"
+)
+
+func OperatorMiddleware(userService service.UserService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err := GetUserID(r.Context())
+			if err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			isOperator, err := userService.IsOperator(userID)
+			if err != nil || !isOperator {
+				http.Error(w, "Operator role is required", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -18,13 +18,15 @@ const (
 )
 
 type Account struct {
-	ID        int64       `json:"id" db:"id"`
-	UserID    int64       `json:"user_id" db:"user_id"`
-	Number    string      `json:"number" db:"number"`
-	Type      AccountType `json:"type" db:"type"`
-	Balance   float64     `json:"balance" db:"balance"`
-	CreatedAt time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+	ID                  int64       `json:"id" db:"id"`
+	UserID              int64       `json:"user_id" db:"user_id"`
+	Number              string      `json:"number" db:"number"`
+	Type                AccountType `json:"type" db:"type"`
+	Balance             float64     `json:"balance" db:"balance"`
+	CreditLimit         float64     `json:"credit_limit" db:"credit_limit"`
+	CreditLineSuspended bool        `json:"credit_line_suspended" db:"credit_line_suspended"`
+	CreatedAt           time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time   `json:"updated_at" db:"updated_at"`
 }
 
 type AccountCreation struct {
@@ -32,11 +34,14 @@ type AccountCreation struct {
 }
 
 type AccountResponse struct {
-	ID        int64       `json:"id"`
-	Number    string      `json:"number"`
-	Type      AccountType `json:"type"`
-	Balance   float64     `json:"balance"`
-	CreatedAt time.Time   `json:"created_at"`
+	ID                  int64       `json:"id"`
+	Number              string      `json:"number"`
+	Type                AccountType `json:"type"`
+	Balance             float64     `json:"balance"`
+	CreditLimit         float64     `json:"credit_limit,omitempty"`
+	AvailableBalance    float64     `json:"available_balance"`
+	CreditLineSuspended bool        `json:"credit_line_suspended,omitempty"`
+	CreatedAt           time.Time   `json:"created_at"`
 }
 
 type DepositRequest struct {
@@ -61,12 +66,29 @@ type BalancePrediction struct {
 	Events  []string  `json:"events,omitempty"`
 }
 
+// AvailableBalance возвращает сумму, доступную для списания. Счет CREDIT может уйти
+// в минус в пределах кредитного лимита, если кредитная линия не приостановлена.
+func (a *Account) AvailableBalance() float64 {
+	if a.Type == AccountTypeCredit && !a.CreditLineSuspended {
+		return a.Balance + a.CreditLimit
+	}
+	return a.Balance
+}
+
+// UsedCredit возвращает использованную часть кредитного лимита
+func (a *Account) UsedCredit() float64 {
+	if a.Balance < 0 {
+		return -a.Balance
+	}
+	return 0
+}
+
 func (a *Account) CanWithdraw(amount float64) error {
 	if amount <= 0 {
 		return ErrInvalidAmount
 	}
 
-	if a.Balance < amount {
+	if a.AvailableBalance() < amount {
 		return ErrInsufficientFunds
 	}
 
@@ -75,10 +97,13 @@ func (a *Account) CanWithdraw(amount float64) error {
 
 func ToAccountResponse(account Account) AccountResponse {
 	return AccountResponse{
-		ID:        account.ID,
-		Number:    account.Number,
-		Type:      account.Type,
-		Balance:   account.Balance,
-		CreatedAt: account.CreatedAt,
+		ID:                  account.ID,
+		Number:              account.Number,
+		Type:                account.Type,
+		Balance:             account.Balance,
+		CreditLimit:         account.CreditLimit,
+		AvailableBalance:    account.AvailableBalance(),
+		CreditLineSuspended: account.CreditLineSuspended,
+		CreatedAt:           account.CreatedAt,
 	}
 }
@@ -12,18 +12,19 @@ const (
 )
 
 type Card struct {
-	ID         int64     `json:"id" db:"id"`
-	AccountID  int64     `json:"account_id" db:"account_id"`
-	UserID     int64     `json:"user_id" db:"user_id"`
-	Number     string    `json:"-" db:"number_encrypted"`
-	NumberHMAC string    `json:"-" db:"number_hmac"`
-	ExpiryDate string    `json:"-" db:"expiry_date_encrypted"`
-	ExpiryHMAC string    `json:"-" db:"expiry_date_hmac"`
-	CVV        string    `json:"-" db:"cvv_hash"`
-	Type       CardType  `json:"type" db:"type"`
-	IsActive   bool      `json:"is_active" db:"is_active"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ID                   int64     `json:"id" db:"id"`
+	AccountID            int64     `json:"account_id" db:"account_id"`
+	UserID               int64     `json:"user_id" db:"user_id"`
+	Number               string    `json:"-" db:"number_encrypted"`
+	NumberHMAC           string    `json:"-" db:"number_hmac"`
+	ExpiryDate           string    `json:"-" db:"expiry_date_encrypted"`
+	ExpiryHMAC           string    `json:"-" db:"expiry_date_hmac"`
+	CVV                  string    `json:"-" db:"cvv_hash"`
+	Type                 CardType  `json:"type" db:"type"`
+	IsActive             bool      `json:"is_active" db:"is_active"`
+	BlockedByCollections bool      `json:"blocked_by_collections" db:"blocked_by_collections"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type CardCreation struct {
@@ -32,13 +33,14 @@ type CardCreation struct {
 }
 
 type CardResponse struct {
-	ID         int64     `json:"id"`
-	AccountID  int64     `json:"account_id"`
-	Number     string    `json:"number"`
-	ExpiryDate string    `json:"expiry_date"`
-	Type       CardType  `json:"type"`
-	IsActive   bool      `json:"is_active"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID                   int64     `json:"id"`
+	AccountID            int64     `json:"account_id"`
+	Number               string    `json:"number"`
+	ExpiryDate           string    `json:"expiry_date"`
+	Type                 CardType  `json:"type"`
+	IsActive             bool      `json:"is_active"`
+	BlockedByCollections bool      `json:"blocked_by_collections"` // заблокирована до погашения просрочки по кредиту
+	CreatedAt            time.Time `json:"created_at"`
 }
 
 type CardPaymentRequest struct {
@@ -46,6 +48,41 @@ type CardPaymentRequest struct {
 	Amount float64 `json:"amount"`
 }
 
+type CardHoldStatus string
+
+const (
+	CardHoldStatusActive   CardHoldStatus = "ACTIVE"
+	CardHoldStatusCaptured CardHoldStatus = "CAPTURED"
+	CardHoldStatusReleased CardHoldStatus = "RELEASED"
+	CardHoldStatusReversed CardHoldStatus = "REVERSED"
+)
+
+// CardHold - авторизация по карте, поступившая от терминала
+type CardHold struct {
+	ID            int64          `json:"id" db:"id"`
+	CardID        int64          `json:"card_id" db:"card_id"`
+	AccountID     int64          `json:"account_id" db:"account_id"`
+	Amount        float64        `json:"amount" db:"amount"`
+	RRN           string         `json:"rrn" db:"rrn"`
+	AuthCode      string         `json:"auth_code" db:"auth_code"`
+	TerminalID    string         `json:"terminal_id" db:"terminal_id"`
+	MerchantName  string         `json:"merchant_name" db:"merchant_name"`
+	Status        CardHoldStatus `json:"status" db:"status"`
+	TransactionID *int64         `json:"transaction_id,omitempty" db:"transaction_id"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// TerminalRequest - карточная операция от терминала (card present), без JWT пользователя
+type TerminalRequest struct {
+	PAN          string
+	Expiry       string // MM/YY
+	Amount       float64
+	RRN          string
+	TerminalID   string
+	MerchantName string
+}
+
 // Для безопасного отображения номера карты (только последние 4 цифры)
 func MaskCardNumber(number string) string {
 	if len(number) < 4 {
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+)
+
+// CollectionBucket - корзина просроченной задолженности по числу дней просрочки
+type CollectionBucket string
+
+const (
+	CollectionBucket1To30  CollectionBucket = "DPD_1_30"    // напоминания
+	CollectionBucket31To60 CollectionBucket = "DPD_31_60"   // повышенные штрафы и пени
+	CollectionBucket61To90 CollectionBucket = "DPD_61_90"   // блокировка карт и новых заявок
+	CollectionBucket90Plus CollectionBucket = "DPD_90_PLUS" // досудебное требование
+)
+
+// CollectionBuckets перечисляет корзины в порядке эскалации
+var CollectionBuckets = []CollectionBucket{
+	CollectionBucket1To30,
+	CollectionBucket31To60,
+	CollectionBucket61To90,
+	CollectionBucket90Plus,
+}
+
+// CollectionBucketFor возвращает корзину для числа дней просрочки
+func CollectionBucketFor(daysPastDue int) CollectionBucket {
+	switch {
+	case daysPastDue > 90:
+		return CollectionBucket90Plus
+	case daysPastDue > 60:
+		return CollectionBucket61To90
+	case daysPastDue > 30:
+		return CollectionBucket31To60
+	default:
+		return CollectionBucket1To30
+	}
+}
+
+// Level возвращает номер корзины в порядке эскалации, начиная с 1 (0 - неизвестная корзина)
+func (b CollectionBucket) Level() int {
+	for i, bucket := range CollectionBuckets {
+		if bucket == b {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (b CollectionBucket) IsValid() bool {
+	return b.Level() > 0
+}
+
+type CollectionCaseStatus string
+
+const (
+	CollectionCaseOpen     CollectionCaseStatus = "OPEN"
+	CollectionCaseResolved CollectionCaseStatus = "RESOLVED"
+)
+
+type CollectionAction string
+
+const (
+	CollectionActionReminder       CollectionAction = "REMINDER"
+	CollectionActionEscalationFine CollectionAction = "ESCALATION_FINE"
+	CollectionActionCardsBlocked   CollectionAction = "CARDS_BLOCKED"
+	CollectionActionFinalNotice    CollectionAction = "FINAL_NOTICE"
+	CollectionActionResolved       CollectionAction = "RESOLVED"
+)
+
+// CollectionCase - дело по просроченному кредиту. Открывается при первой просрочке
+// и закрывается, когда все просроченные платежи погашены.
+type CollectionCase struct {
+	ID              int64                `json:"id" db:"id"`
+	CreditID        int64                `json:"credit_id" db:"credit_id"`
+	UserID          int64                `json:"user_id" db:"user_id"`
+	DaysPastDue     int                  `json:"days_past_due" db:"days_past_due"`
+	Bucket          CollectionBucket     `json:"bucket" db:"bucket"`
+	OverdueAmount   float64              `json:"overdue_amount" db:"overdue_amount"`
+	OverdueSince    time.Time            `json:"overdue_since" db:"overdue_since"`
+	Status          CollectionCaseStatus `json:"status" db:"status"`
+	CardsBlocked    bool                 `json:"cards_blocked" db:"cards_blocked"`
+	EscalatedBucket CollectionBucket     `json:"escalated_bucket" db:"escalated_bucket"` // старшая корзина, меры которой уже применены
+	CreatedAt       time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at" db:"updated_at"`
+	ResolvedAt      *time.Time           `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+// CollectionCaseAction - мера, принятая по делу при переходе в корзину
+type CollectionCaseAction struct {
+	ID        int64            `json:"id" db:"id"`
+	CaseID    int64            `json:"case_id" db:"case_id"`
+	Bucket    CollectionBucket `json:"bucket" db:"bucket"`
+	Action    CollectionAction `json:"action" db:"action"`
+	Details   string           `json:"details" db:"details"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
+
+// CollectionBucketSummary - просроченные кредиты одной корзины
+type CollectionBucketSummary struct {
+	Bucket        CollectionBucket `json:"bucket"`
+	Count         int              `json:"count"`
+	OverdueAmount float64          `json:"overdue_amount"`
+	Cases         []CollectionCase `json:"cases"`
+}
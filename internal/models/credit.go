@@ -15,56 +15,218 @@ const (
 	CreditStatusPending  CreditStatus = "PENDING"
 )
 
+// creditTransitions задает допустимые переходы между статусами кредита
+var creditTransitions = map[CreditStatus][]CreditStatus{
+	CreditStatusPending:  {CreditStatusApproved, CreditStatusRejected},
+	CreditStatusApproved: {CreditStatusActive},
+	CreditStatusActive:   {CreditStatusOverdue, CreditStatusClosed},
+	CreditStatusOverdue:  {CreditStatusActive, CreditStatusClosed},
+}
+
+func (s CreditStatus) CanTransitionTo(next CreditStatus) bool {
+	for _, allowed := range creditTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type CreditStatusHistory struct {
+	ID         int64         `json:"id" db:"id"`
+	CreditID   int64         `json:"credit_id" db:"credit_id"`
+	FromStatus *CreditStatus `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   CreditStatus  `json:"to_status" db:"to_status"`
+	Reason     string        `json:"reason" db:"reason"`
+	ChangedAt  time.Time     `json:"changed_at" db:"changed_at"`
+}
+
+// CreditRateHistory - ставка кредита, действующая с даты effective_from. Для кредита с
+// плавающей ставкой новая запись появляется при каждом изменении ключевой ставки.
+type CreditRateHistory struct {
+	ID             int64     `json:"id" db:"id"`
+	CreditID       int64     `json:"credit_id" db:"credit_id"`
+	KeyRate        float64   `json:"key_rate" db:"key_rate"`
+	InterestRate   float64   `json:"interest_rate" db:"interest_rate"`
+	MonthlyPayment float64   `json:"monthly_payment" db:"monthly_payment"`
+	EffectiveFrom  time.Time `json:"effective_from" db:"effective_from"`
+	Reason         string    `json:"reason" db:"reason"`
+	ChangedAt      time.Time `json:"changed_at" db:"changed_at"`
+}
+
+type RepaymentType string
+
+const (
+	RepaymentTypeAnnuity        RepaymentType = "ANNUITY"
+	RepaymentTypeDifferentiated RepaymentType = "DIFFERENTIATED"
+)
+
+func (t RepaymentType) IsValid() bool {
+	return t == RepaymentTypeAnnuity || t == RepaymentTypeDifferentiated
+}
+
 type Credit struct {
-	ID             int64        `json:"id" db:"id"`
-	UserID         int64        `json:"user_id" db:"user_id"`
-	AccountID      int64        `json:"account_id" db:"account_id"`
-	Amount         float64      `json:"amount" db:"amount"`
-	Term           int          `json:"term" db:"term"`
-	InterestRate   float64      `json:"interest_rate" db:"interest_rate"`
-	MonthlyPayment float64      `json:"monthly_payment" db:"monthly_payment"`
-	TotalPayment   float64      `json:"total_payment" db:"total_payment"`
-	Status         CreditStatus `json:"status" db:"status"`
-	StartDate      time.Time    `json:"start_date" db:"start_date"`
-	EndDate        time.Time    `json:"end_date" db:"end_date"`
-	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+	ID             int64              `json:"id" db:"id"`
+	UserID         int64              `json:"user_id" db:"user_id"`
+	AccountID      int64              `json:"account_id" db:"account_id"`
+	ProductID      int64              `json:"product_id" db:"product_id"`
+	Amount         float64            `json:"amount" db:"amount"`
+	IssuanceFee    float64            `json:"issuance_fee" db:"issuance_fee"`
+	Term           int                `json:"term" db:"term"`
+	InterestRate   float64            `json:"interest_rate" db:"interest_rate"`
+	IsFloating     bool               `json:"is_floating" db:"is_floating"`
+	KeyRate        float64            `json:"key_rate" db:"key_rate"`
+	KeyRateStale   bool               `json:"key_rate_stale" db:"key_rate_stale"`
+	RateSpread     float64            `json:"rate_spread" db:"rate_spread"`
+	DayCount       DayCountConvention `json:"day_count" db:"day_count"`
+	RepaymentType  RepaymentType      `json:"repayment_type" db:"repayment_type"`
+	MonthlyPayment float64            `json:"monthly_payment" db:"monthly_payment"`
+	TotalPayment   float64            `json:"total_payment" db:"total_payment"`
+	FullCostRate   float64            `json:"full_cost_rate" db:"full_cost_rate"`
+	FullCostAmount float64            `json:"full_cost_amount" db:"full_cost_amount"`
+	Status         CreditStatus       `json:"status" db:"status"`
+	DeclaredIncome float64            `json:"declared_income" db:"declared_income"`
+	Score          int                `json:"score" db:"score"`
+	DecisionReason string             `json:"decision_reason" db:"decision_reason"`
+	RateAdjustment float64            `json:"rate_adjustment" db:"rate_adjustment"`
+	DecidedAt      *time.Time         `json:"decided_at,omitempty" db:"decided_at"`
+	StartDate      time.Time          `json:"start_date" db:"start_date"`
+	EndDate        time.Time          `json:"end_date" db:"end_date"`
+	CreatedAt      time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at" db:"updated_at"`
 }
 
 type CreditApplication struct {
-	AccountID int64   `json:"account_id"`
-	Amount    float64 `json:"amount"`
-	Term      int     `json:"term"`
+	AccountID      int64         `json:"account_id"`
+	ProductID      int64         `json:"product_id"`
+	Amount         float64       `json:"amount"`
+	Term           int           `json:"term"`
+	DeclaredIncome float64       `json:"declared_income"`
+	RepaymentType  RepaymentType `json:"repayment_type"`
+}
+
+// CreditQuoteRequest - расчет условий кредита без оформления. Можно передать
+// несколько сроков и типов погашения для сравнения.
+type CreditQuoteRequest struct {
+	ProductID      int64           `json:"product_id"`
+	Amount         float64         `json:"amount"`
+	Term           int             `json:"term"`
+	Terms          []int           `json:"terms"`
+	RepaymentType  RepaymentType   `json:"repayment_type"`
+	RepaymentTypes []RepaymentType `json:"repayment_types"`
+}
+
+type CreditQuote struct {
+	ProductID      int64                     `json:"product_id"`
+	Amount         float64                   `json:"amount"`
+	IssuanceFee    float64                   `json:"issuance_fee"`
+	Term           int                       `json:"term"`
+	RepaymentType  RepaymentType             `json:"repayment_type"`
+	InterestRate   float64                   `json:"interest_rate"`
+	IsFloating     bool                      `json:"is_floating"`
+	KeyRate        float64                   `json:"key_rate"`
+	KeyRateStale   bool                      `json:"key_rate_stale"`
+	MonthlyPayment float64                   `json:"monthly_payment"`
+	TotalPayment   float64                   `json:"total_payment"`
+	Overpayment    float64                   `json:"overpayment"`
+	FullCostRate   float64                   `json:"full_cost_rate"`
+	FullCostAmount float64                   `json:"full_cost_amount"`
+	ExceedsCap     bool                      `json:"exceeds_full_cost_cap"`
+	Schedule       []PaymentScheduleResponse `json:"schedule"`
+}
+
+type EarlyRepaymentMode string
+
+const (
+	EarlyRepaymentReduceTerm    EarlyRepaymentMode = "REDUCE_TERM"
+	EarlyRepaymentReducePayment EarlyRepaymentMode = "REDUCE_PAYMENT"
+)
+
+type EarlyRepaymentRequest struct {
+	Amount float64            `json:"amount"`
+	Full   bool               `json:"full"`
+	Mode   EarlyRepaymentMode `json:"mode"`
 }
 
 type CreditResponse struct {
-	ID             int64        `json:"id"`
-	Amount         float64      `json:"amount"`
-	Term           int          `json:"term"`
-	InterestRate   float64      `json:"interest_rate"`
-	MonthlyPayment float64      `json:"monthly_payment"`
-	TotalPayment   float64      `json:"total_payment"`
-	Status         CreditStatus `json:"status"`
-	StartDate      time.Time    `json:"start_date"`
-	EndDate        time.Time    `json:"end_date"`
+	ID             int64              `json:"id"`
+	ProductID      int64              `json:"product_id"`
+	Amount         float64            `json:"amount"`
+	IssuanceFee    float64            `json:"issuance_fee"`
+	Term           int                `json:"term"`
+	InterestRate   float64            `json:"interest_rate"`
+	IsFloating     bool               `json:"is_floating"`
+	KeyRate        float64            `json:"key_rate"`
+	KeyRateStale   bool               `json:"key_rate_stale"`
+	DayCount       DayCountConvention `json:"day_count"`
+	RepaymentType  RepaymentType      `json:"repayment_type"`
+	MonthlyPayment float64            `json:"monthly_payment"`
+	TotalPayment   float64            `json:"total_payment"`
+	FullCostRate   float64            `json:"full_cost_rate"`
+	FullCostAmount float64            `json:"full_cost_amount"`
+	Status         CreditStatus       `json:"status"`
+	Score          int                `json:"score"`
+	DecisionReason string             `json:"decision_reason,omitempty"`
+	StartDate      time.Time          `json:"start_date"`
+	EndDate        time.Time          `json:"end_date"`
 }
 
 type CreditAnalytics struct {
-	TotalDebt         float64 `json:"total_debt"`
-	MonthlyPayments   float64 `json:"monthly_payments"`
-	DebtToIncomeRatio float64 `json:"debt_to_income_ratio"`
-	RemainingCredits  int     `json:"remaining_credits"`
+	TotalDebt         float64        `json:"total_debt"`
+	MonthlyPayments   float64        `json:"monthly_payments"`
+	DebtToIncomeRatio float64        `json:"debt_to_income_ratio"`
+	RemainingCredits  int            `json:"remaining_credits"`
+	Income            IncomeEstimate `json:"income"`
+}
+
+type IncomeMethod string
+
+const (
+	IncomeMethodTransactions IncomeMethod = "TRANSACTIONS" // поступления на счета за последние месяцы
+	IncomeMethodDeclared     IncomeMethod = "DECLARED"     // доход, указанный в профиле
+	IncomeMethodNone         IncomeMethod = "NONE"         // данных о доходе нет, долговая нагрузка не рассчитывается
+)
+
+type IncomeConfidence string
+
+const (
+	IncomeConfidenceHigh   IncomeConfidence = "HIGH"
+	IncomeConfidenceMedium IncomeConfidence = "MEDIUM"
+	IncomeConfidenceLow    IncomeConfidence = "LOW"
+	IncomeConfidenceNone   IncomeConfidence = "NONE"
+)
+
+// IncomeEstimate - оценка ежемесячного дохода, по которой считается долговая нагрузка
+type IncomeEstimate struct {
+	MonthlyIncome    float64          `json:"monthly_income"`
+	Method           IncomeMethod     `json:"method"`
+	Confidence       IncomeConfidence `json:"confidence"`
+	WindowDays       int              `json:"window_days"`
+	MonthsWithIncome int              `json:"months_with_income"`
+	ObservedIncome   float64          `json:"observed_monthly_income"`
+	DeclaredIncome   float64          `json:"declared_income,omitempty"`
 }
 
 func ToCreditResponse(credit Credit) CreditResponse {
 	return CreditResponse{
 		ID:             credit.ID,
+		ProductID:      credit.ProductID,
 		Amount:         credit.Amount,
+		IssuanceFee:    credit.IssuanceFee,
 		Term:           credit.Term,
 		InterestRate:   credit.InterestRate,
+		IsFloating:     credit.IsFloating,
+		KeyRate:        credit.KeyRate,
+		KeyRateStale:   credit.KeyRateStale,
+		DayCount:       credit.DayCount,
+		RepaymentType:  credit.RepaymentType,
 		MonthlyPayment: credit.MonthlyPayment,
 		TotalPayment:   credit.TotalPayment,
+		FullCostRate:   credit.FullCostRate,
+		FullCostAmount: credit.FullCostAmount,
 		Status:         credit.Status,
+		Score:          credit.Score,
+		DecisionReason: credit.DecisionReason,
 		StartDate:      credit.StartDate,
 		EndDate:        credit.EndDate,
 	}
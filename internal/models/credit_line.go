@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+type CreditLineStatementStatus string
+
+const (
+	StatementStatusOpen        CreditLineStatementStatus = "OPEN"         // срок минимального платежа не наступил
+	StatementStatusPaid        CreditLineStatementStatus = "PAID"         // задолженность погашена до даты платежа
+	StatementStatusMinimumPaid CreditLineStatementStatus = "MINIMUM_PAID" // внесен минимальный платеж
+	StatementStatusOverdue     CreditLineStatementStatus = "OVERDUE"      // минимальный платеж не внесен в срок
+)
+
+// CreditLineStatement - ежемесячная выписка по кредитной линии с минимальным платежом
+type CreditLineStatement struct {
+	ID              int64                     `json:"id" db:"id"`
+	AccountID       int64                     `json:"account_id" db:"account_id"`
+	PeriodStart     time.Time                 `json:"period_start" db:"period_start"`
+	PeriodEnd       time.Time                 `json:"period_end" db:"period_end"`
+	OpeningBalance  float64                   `json:"opening_balance" db:"opening_balance"`
+	ClosingBalance  float64                   `json:"closing_balance" db:"closing_balance"`
+	Debits          float64                   `json:"debits" db:"debits"`
+	Credits         float64                   `json:"credits" db:"credits"`
+	AccruedInterest float64                   `json:"accrued_interest" db:"accrued_interest"`
+	Interest        float64                   `json:"interest" db:"interest"`
+	Debt            float64                   `json:"debt" db:"debt"`
+	MinimumPayment  float64                   `json:"minimum_payment" db:"minimum_payment"`
+	DueDate         time.Time                 `json:"due_date" db:"due_date"`
+	PaidAmount      float64                   `json:"paid_amount" db:"paid_amount"`
+	LateFee         float64                   `json:"late_fee" db:"late_fee"`
+	Status          CreditLineStatementStatus `json:"status" db:"status"`
+	CreatedAt       time.Time                 `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time                 `json:"updated_at" db:"updated_at"`
+}
+
+// CreditLineAccrual - проценты, начисленные за день на использованную часть лимита
+type CreditLineAccrual struct {
+	ID           int64     `json:"id" db:"id"`
+	AccountID    int64     `json:"account_id" db:"account_id"`
+	AccrualDate  time.Time `json:"accrual_date" db:"accrual_date"`
+	UsedAmount   float64   `json:"used_amount" db:"used_amount"`
+	InterestRate float64   `json:"interest_rate" db:"interest_rate"`
+	Amount       float64   `json:"amount" db:"amount"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreditLimitRequest struct {
+	CreditLimit float64 `json:"credit_limit"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// CreditProduct - кредитный продукт с собственными условиями: надбавкой к ключевой
+// ставке, лимитами суммы и срока, комиссиями и требованиями к заемщику
+type CreditProduct struct {
+	ID               int64              `json:"id" db:"id"`
+	Code             string             `json:"code" db:"code"`
+	Name             string             `json:"name" db:"name"`
+	Description      string             `json:"description" db:"description"`
+	RateSpread       float64            `json:"rate_spread" db:"rate_spread"`
+	IsFloating       bool               `json:"is_floating" db:"is_floating"` // ставка пересматривается при изменении ключевой
+	DayCount         DayCountConvention `json:"day_count" db:"day_count"`
+	MinAmount        float64            `json:"min_amount" db:"min_amount"`
+	MaxAmount        float64            `json:"max_amount" db:"max_amount"`
+	MinTerm          int                `json:"min_term" db:"min_term"`
+	MaxTerm          int                `json:"max_term" db:"max_term"`
+	IssuanceFeeRate  float64            `json:"issuance_fee_rate" db:"issuance_fee_rate"`
+	IssuanceFeeFixed float64            `json:"issuance_fee_fixed" db:"issuance_fee_fixed"`
+	MinIncome        float64            `json:"min_income" db:"min_income"`
+	MaxActiveCredits int                `json:"max_active_credits" db:"max_active_credits"`
+	IsActive         bool               `json:"is_active" db:"is_active"`
+	CreatedAt        time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+type CreditProductInput struct {
+	Code             string             `json:"code"`
+	Name             string             `json:"name"`
+	Description      string             `json:"description"`
+	RateSpread       float64            `json:"rate_spread"`
+	IsFloating       bool               `json:"is_floating"`
+	DayCount         DayCountConvention `json:"day_count"`
+	MinAmount        float64            `json:"min_amount"`
+	MaxAmount        float64            `json:"max_amount"`
+	MinTerm          int                `json:"min_term"`
+	MaxTerm          int                `json:"max_term"`
+	IssuanceFeeRate  float64            `json:"issuance_fee_rate"`
+	IssuanceFeeFixed float64            `json:"issuance_fee_fixed"`
+	MinIncome        float64            `json:"min_income"`
+	MaxActiveCredits int                `json:"max_active_credits"`
+	IsActive         bool               `json:"is_active"`
+}
+
+// IssuanceFee возвращает комиссию за выдачу кредита на сумму amount
+func (p CreditProduct) IssuanceFee(amount float64) float64 {
+	return amount*p.IssuanceFeeRate/100 + p.IssuanceFeeFixed
+}
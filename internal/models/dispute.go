@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+)
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpened      DisputeStatus = "OPENED"
+	DisputeStatusUnderReview DisputeStatus = "UNDER_REVIEW"
+	DisputeStatusAccepted    DisputeStatus = "ACCEPTED"
+	DisputeStatusRejected    DisputeStatus = "REJECTED"
+)
+
+type DisputeReasonCode string
+
+const (
+	DisputeReasonFraud           DisputeReasonCode = "FRAUD"
+	DisputeReasonDuplicate       DisputeReasonCode = "DUPLICATE"
+	DisputeReasonNotReceived     DisputeReasonCode = "NOT_RECEIVED"
+	DisputeReasonNotAsDescribed  DisputeReasonCode = "NOT_AS_DESCRIBED"
+	DisputeReasonIncorrectAmount DisputeReasonCode = "INCORRECT_AMOUNT"
+	DisputeReasonCanceled        DisputeReasonCode = "CANCELED"
+)
+
+type CardDispute struct {
+	ID                    int64             `json:"id" db:"id"`
+	UserID                int64             `json:"user_id" db:"user_id"`
+	TransactionID         int64             `json:"transaction_id" db:"transaction_id"`
+	AccountID             int64             `json:"account_id" db:"account_id"`
+	Amount                float64           `json:"amount" db:"amount"`
+	ReasonCode            DisputeReasonCode `json:"reason_code" db:"reason_code"`
+	Description           string            `json:"description" db:"description"`
+	Status                DisputeStatus     `json:"status" db:"status"`
+	ResolutionComment     string            `json:"resolution_comment" db:"resolution_comment"`
+	ProvisionalCreditTxID *int64            `json:"provisional_credit_tx_id,omitempty" db:"provisional_credit_tx_id"`
+	FinalReversalAt       *time.Time        `json:"final_reversal_at,omitempty" db:"final_reversal_at"`
+	ResolvedAt            *time.Time        `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt             time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+type DisputeCreation struct {
+	TransactionID int64             `json:"transaction_id"`
+	ReasonCode    DisputeReasonCode `json:"reason_code"`
+	Description   string            `json:"description"`
+}
+
+type DisputeResolution struct {
+	Decision DisputeStatus `json:"decision"`
+	Comment  string        `json:"comment"`
+}
+
+type DisputeResponse struct {
+	ID                int64             `json:"id"`
+	TransactionID     int64             `json:"transaction_id"`
+	AccountID         int64             `json:"account_id"`
+	Amount            float64           `json:"amount"`
+	ReasonCode        DisputeReasonCode `json:"reason_code"`
+	Description       string            `json:"description"`
+	Status            DisputeStatus     `json:"status"`
+	ResolutionComment string            `json:"resolution_comment,omitempty"`
+	ProvisionalCredit bool              `json:"provisional_credit"`
+	FinalReversalAt   *time.Time        `json:"final_reversal_at,omitempty"`
+	ResolvedAt        *time.Time        `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+}
+
+func (c DisputeReasonCode) IsValid() bool {
+	switch c {
+	case DisputeReasonFraud, DisputeReasonDuplicate, DisputeReasonNotReceived,
+		DisputeReasonNotAsDescribed, DisputeReasonIncorrectAmount, DisputeReasonCanceled:
+		return true
+	}
+	return false
+}
+
+func ToDisputeResponse(dispute CardDispute) DisputeResponse {
+	return DisputeResponse{
+		ID:                dispute.ID,
+		TransactionID:     dispute.TransactionID,
+		AccountID:         dispute.AccountID,
+		Amount:            dispute.Amount,
+		ReasonCode:        dispute.ReasonCode,
+		Description:       dispute.Description,
+		Status:            dispute.Status,
+		ResolutionComment: dispute.ResolutionComment,
+		ProvisionalCredit: dispute.ProvisionalCreditTxID != nil,
+		FinalReversalAt:   dispute.FinalReversalAt,
+		ResolvedAt:        dispute.ResolvedAt,
+		CreatedAt:         dispute.CreatedAt,
+	}
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+)
+
+// DayCountConvention - база начисления процентов: как длина периода переводится в долю года
+type DayCountConvention string
+
+const (
+	DayCount30360     DayCountConvention = "30/360"        // месяц - 1/12 года, неполный месяц - дни/360
+	DayCountActual365 DayCountConvention = "ACTUAL/365"    // фактические дни/365
+	DayCountActualAct DayCountConvention = "ACTUAL/ACTUAL" // фактические дни/число дней в году (365 или 366)
+)
+
+func (c DayCountConvention) IsValid() bool {
+	return c == DayCount30360 || c == DayCountActual365 || c == DayCountActualAct
+}
+
+// YearFraction возвращает долю года между from и to по базе начисления
+func (c DayCountConvention) YearFraction(from, to time.Time) float64 {
+	if !to.After(from) {
+		return 0
+	}
+
+	switch c {
+	case DayCountActual365:
+		return daysBetween(from, to) / 365
+	case DayCountActualAct:
+		// Дни каждого календарного года делятся на длину этого года
+		fraction := 0.0
+		for from.Before(to) {
+			yearStart := time.Date(from.Year(), time.January, 1, 0, 0, 0, 0, from.Location())
+			yearEnd := yearStart.AddDate(1, 0, 0)
+			end := to
+			if yearEnd.Before(end) {
+				end = yearEnd
+			}
+			fraction += daysBetween(from, end) / daysBetween(yearStart, yearEnd)
+			from = end
+		}
+		return fraction
+	default:
+		months, periodStart := WholeMonthsBetween(from, to)
+		return float64(months)/12 + daysBetween(periodStart, to)/360
+	}
+}
+
+// WholeMonthsBetween возвращает число полных месяцев от from до to и дату окончания
+// последнего из них
+func WholeMonthsBetween(from, to time.Time) (int, time.Time) {
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	end := AddMonths(from, months)
+	for months > 0 && end.After(to) {
+		months--
+		end = AddMonths(from, months)
+	}
+	return months, end
+}
+
+// AddMonths прибавляет к дате months месяцев. Если в получившемся месяце нет такого
+// числа, берется его последний день: 31 января + 1 месяц = 28 (29) февраля, а не 3 марта.
+func AddMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	firstDay := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if lastDay := firstDay.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return firstDay.AddDate(0, 0, day-1)
+}
+
+func daysBetween(from, to time.Time) float64 {
+	return to.Sub(from).Hours() / 24
+}
+
+// CreditInterestAccrual - проценты, начисленные по кредиту за один день
+type CreditInterestAccrual struct {
+	ID           int64              `json:"id" db:"id"`
+	CreditID     int64              `json:"credit_id" db:"credit_id"`
+	AccrualDate  time.Time          `json:"accrual_date" db:"accrual_date"`
+	Principal    float64            `json:"principal" db:"principal"`
+	InterestRate float64            `json:"interest_rate" db:"interest_rate"`
+	DayCount     DayCountConvention `json:"day_count" db:"day_count"`
+	Amount       float64            `json:"amount" db:"amount"`
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+}
+
+// InterestOwed - проценты к уплате по кредиту на дату AsOf
+type InterestOwed struct {
+	CreditID             int64                   `json:"credit_id"`
+	AsOf                 time.Time               `json:"as_of"`
+	DayCount             DayCountConvention      `json:"day_count"`
+	InterestRate         float64                 `json:"interest_rate"`
+	PeriodStart          time.Time               `json:"period_start"`     // начало текущего процентного периода
+	Principal            float64                 `json:"principal"`        // остаток основного долга, на который начисляются проценты
+	AccruedInterest      float64                 `json:"accrued_interest"` // проценты текущего периода на дату AsOf
+	UnpaidInterest       float64                 `json:"unpaid_interest"`  // проценты наступивших и не оплаченных платежей
+	TotalInterest        float64                 `json:"total_interest"`   // все проценты к уплате
+	OutstandingPrincipal float64                 `json:"outstanding_principal"`
+	PayoffAmount         float64                 `json:"payoff_amount"` // сумма полного погашения без штрафов и пеней
+	DailyAccruals        []CreditInterestAccrual `json:"daily_accruals,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// KeyRate - ключевая ставка ЦБ РФ
+type KeyRate struct {
+	Rate      float64   `json:"rate"`
+	Date      time.Time `json:"date"`       // дата, с которой действует ставка
+	FetchedAt time.Time `json:"fetched_at"` // когда ставка получена от ЦБ РФ
+	Stale     bool      `json:"stale"`      // ЦБ РФ не ответил, ставка взята из сохраненной или резервной
+}
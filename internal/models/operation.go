@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OperationType string
+
+const (
+	OperationTypeTransfer    OperationType = "TRANSFER"
+	OperationTypeWithdraw    OperationType = "WITHDRAW"
+	OperationTypeCardPayment OperationType = "CARD_PAYMENT"
+)
+
+type OperationStatus string
+
+const (
+	OperationStatusPending   OperationStatus = "PENDING"
+	OperationStatusConfirmed OperationStatus = "CONFIRMED"
+	OperationStatusFailed    OperationStatus = "FAILED"
+	OperationStatusLocked    OperationStatus = "LOCKED"
+	OperationStatusExpired   OperationStatus = "EXPIRED"
+)
+
+type PendingOperation struct {
+	ID          int64           `json:"id" db:"id"`
+	UserID      int64           `json:"user_id" db:"user_id"`
+	Type        OperationType   `json:"type" db:"type"`
+	Amount      float64         `json:"amount" db:"amount"`
+	Payload     json.RawMessage `json:"-" db:"payload"`
+	CodeHash    string          `json:"-" db:"code_hash"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	Status      OperationStatus `json:"status" db:"status"`
+	ExpiresAt   time.Time       `json:"expires_at" db:"expires_at"`
+	ConfirmedAt *time.Time      `json:"confirmed_at,omitempty" db:"confirmed_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+type OperationConfirmation struct {
+	Code string `json:"code"`
+}
+
+type PendingOperationResponse struct {
+	ID                int64           `json:"operation_id"`
+	Type              OperationType   `json:"type"`
+	Amount            float64         `json:"amount"`
+	Status            OperationStatus `json:"status"`
+	AttemptsRemaining int             `json:"attempts_remaining"`
+	ExpiresAt         time.Time       `json:"expires_at"`
+}
+
+func ToPendingOperationResponse(operation PendingOperation, maxAttempts int) PendingOperationResponse {
+	remaining := maxAttempts - operation.Attempts
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return PendingOperationResponse{
+		ID:                operation.ID,
+		Type:              operation.Type,
+		Amount:            operation.Amount,
+		Status:            operation.Status,
+		AttemptsRemaining: remaining,
+		ExpiresAt:         operation.ExpiresAt,
+	}
+}
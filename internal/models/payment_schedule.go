@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 )
 
@@ -22,29 +23,43 @@ type PaymentSchedule struct {
 	Interest      float64       `json:"interest" db:"interest"`
 	RemainingDebt float64       `json:"remaining_debt" db:"remaining_debt"`
 	Status        PaymentStatus `json:"status" db:"status"`
+	PaidAmount    float64       `json:"paid_amount" db:"paid_amount"`
 	PaidDate      *time.Time    `json:"paid_date,omitempty" db:"paid_date"`
 	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
 }
 
 type PaymentScheduleResponse struct {
-	PaymentDate   time.Time     `json:"payment_date"`
-	Amount        float64       `json:"amount"`
-	Principal     float64       `json:"principal"`
-	Interest      float64       `json:"interest"`
-	RemainingDebt float64       `json:"remaining_debt"`
-	Status        PaymentStatus `json:"status"`
-	PaidDate      *time.Time    `json:"paid_date,omitempty"`
+	PaymentDate       time.Time               `json:"payment_date"`
+	Amount            float64                 `json:"amount"`
+	Principal         float64                 `json:"principal"`
+	Interest          float64                 `json:"interest"`
+	RemainingDebt     float64                 `json:"remaining_debt"`
+	Status            PaymentStatus           `json:"status"`
+	PaidAmount        float64                 `json:"paid_amount"`
+	OutstandingAmount float64                 `json:"outstanding_amount"`
+	PaidDate          *time.Time              `json:"paid_date,omitempty"`
+	Penalties         []CreditPenaltyResponse `json:"penalties,omitempty"`
 }
 
 func ToPaymentScheduleResponse(schedule PaymentSchedule) PaymentScheduleResponse {
 	return PaymentScheduleResponse{
-		PaymentDate:   schedule.PaymentDate,
-		Amount:        schedule.Amount,
-		Principal:     schedule.Principal,
-		Interest:      schedule.Interest,
-		RemainingDebt: schedule.RemainingDebt,
-		Status:        schedule.Status,
-		PaidDate:      schedule.PaidDate,
+		PaymentDate:       schedule.PaymentDate,
+		Amount:            schedule.Amount,
+		Principal:         schedule.Principal,
+		Interest:          schedule.Interest,
+		RemainingDebt:     schedule.RemainingDebt,
+		Status:            schedule.Status,
+		PaidAmount:        schedule.PaidAmount,
+		OutstandingAmount: schedule.Outstanding(),
+		PaidDate:          schedule.PaidDate,
 	}
 }
+
+// Outstanding возвращает еще не внесенную часть платежа
+func (p PaymentSchedule) Outstanding() float64 {
+	if p.Status == PaymentStatusPaid || p.Status == PaymentStatusCanceled {
+		return 0
+	}
+	return math.Max(p.Amount-p.PaidAmount, 0)
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+type PenaltyType string
+
+const (
+	PenaltyTypeFine     PenaltyType = "FINE"
+	PenaltyTypeInterest PenaltyType = "INTEREST"
+)
+
+type PenaltyStatus string
+
+const (
+	PenaltyStatusUnpaid PenaltyStatus = "UNPAID"
+	PenaltyStatusPaid   PenaltyStatus = "PAID"
+)
+
+// CreditPenalty - разовый штраф (FINE) или пени (INTEREST) по просроченному платежу
+type CreditPenalty struct {
+	ID                int64         `json:"id" db:"id"`
+	CreditID          int64         `json:"credit_id" db:"credit_id"`
+	PaymentScheduleID int64         `json:"payment_schedule_id" db:"payment_schedule_id"`
+	Type              PenaltyType   `json:"type" db:"type"`
+	Amount            float64       `json:"amount" db:"amount"`
+	AccrualDate       time.Time     `json:"accrual_date" db:"accrual_date"`
+	Status            PenaltyStatus `json:"status" db:"status"`
+	PaidDate          *time.Time    `json:"paid_date,omitempty" db:"paid_date"`
+	CreatedAt         time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+type CreditPenaltyResponse struct {
+	Type        PenaltyType   `json:"type"`
+	Amount      float64       `json:"amount"`
+	AccrualDate time.Time     `json:"accrual_date"`
+	Status      PenaltyStatus `json:"status"`
+	PaidDate    *time.Time    `json:"paid_date,omitempty"`
+}
+
+func ToCreditPenaltyResponse(penalty CreditPenalty) CreditPenaltyResponse {
+	return CreditPenaltyResponse{
+		Type:        penalty.Type,
+		Amount:      penalty.Amount,
+		AccrualDate: penalty.AccrualDate,
+		Status:      penalty.Status,
+		PaidDate:    penalty.PaidDate,
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+type RestructuringType string
+
+const (
+	RestructuringPaymentHoliday RestructuringType = "PAYMENT_HOLIDAY" // отсрочка платежей с капитализацией процентов
+	RestructuringExtendTerm     RestructuringType = "EXTEND_TERM"     // увеличение срока со снижением платежа
+)
+
+type CreditRestructuringRequest struct {
+	Type   RestructuringType `json:"type"`
+	Months int               `json:"months"`
+	Reason string            `json:"reason"`
+}
+
+// CreditRestructuring - запись о реструктуризации кредита с условиями до и после нее
+type CreditRestructuring struct {
+	ID                   int64             `json:"id" db:"id"`
+	CreditID             int64             `json:"credit_id" db:"credit_id"`
+	Type                 RestructuringType `json:"type" db:"type"`
+	Months               int               `json:"months" db:"months"`
+	PrincipalBefore      float64           `json:"principal_before" db:"principal_before"`
+	CapitalizedInterest  float64           `json:"capitalized_interest" db:"capitalized_interest"`
+	PrincipalAfter       float64           `json:"principal_after" db:"principal_after"`
+	TermBefore           int               `json:"term_before" db:"term_before"`
+	TermAfter            int               `json:"term_after" db:"term_after"`
+	MonthlyPaymentBefore float64           `json:"monthly_payment_before" db:"monthly_payment_before"`
+	MonthlyPaymentAfter  float64           `json:"monthly_payment_after" db:"monthly_payment_after"`
+	FirstPaymentDate     time.Time         `json:"first_payment_date" db:"first_payment_date"`
+	Reason               string            `json:"reason" db:"reason"`
+	CreatedAt            time.Time         `json:"created_at" db:"created_at"`
+}
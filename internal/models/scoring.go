@@ -0,0 +1,38 @@
+package models
+
+type ScoringDecision string
+
+const (
+	ScoringDecisionApproved ScoringDecision = "APPROVED"
+	ScoringDecisionRejected ScoringDecision = "REJECTED"
+	ScoringDecisionReview   ScoringDecision = "REVIEW"
+)
+
+// ScoringInput содержит данные заемщика, по которым принимается решение о выдаче кредита
+type ScoringInput struct {
+	UserID                  int64
+	Amount                  float64
+	Term                    int
+	InterestRate            float64
+	MonthlyPayment          float64
+	DeclaredIncome          float64
+	MonthlyTurnover         float64
+	ExistingDebt            float64
+	ExistingMonthlyPayments float64
+	CurrentOverdue          int
+	OverdueHistory          int
+}
+
+type ScoringResult struct {
+	Decision       ScoringDecision
+	Score          int
+	Reason         string
+	RateAdjustment float64
+}
+
+// CreditDecisionRequest - решение оператора по заявке из очереди ручной проверки
+type CreditDecisionRequest struct {
+	Decision       ScoringDecision `json:"decision"`
+	Reason         string          `json:"reason"`
+	RateAdjustment *float64        `json:"rate_adjustment,omitempty"`
+}
@@ -7,33 +7,46 @@ import (
 type TransactionType string
 
 const (
-	TransactionTypeDeposit  TransactionType = "DEPOSIT"
-	TransactionTypeWithdraw TransactionType = "WITHDRAW"
-	TransactionTypeTransfer TransactionType = "TRANSFER"
-	TransactionTypePayment  TransactionType = "PAYMENT"
-	TransactionTypeCredit   TransactionType = "CREDIT"
+	TransactionTypeDeposit    TransactionType = "DEPOSIT"
+	TransactionTypeWithdraw   TransactionType = "WITHDRAW"
+	TransactionTypeTransfer   TransactionType = "TRANSFER"
+	TransactionTypePayment    TransactionType = "PAYMENT"
+	TransactionTypeCredit     TransactionType = "CREDIT"
+	TransactionTypeChargeback TransactionType = "CHARGEBACK"
+)
+
+const (
+	TransactionStatusCompleted   = "COMPLETED"
+	TransactionStatusProvisional = "PROVISIONAL"
+	TransactionStatusReversed    = "REVERSED"
 )
 
 type Transaction struct {
-	ID              int64           `json:"id" db:"id"`
-	UserID          int64           `json:"user_id" db:"user_id"`
-	FromAccountID   *int64          `json:"from_account_id,omitempty" db:"from_account_id"`
-	ToAccountID     *int64          `json:"to_account_id,omitempty" db:"to_account_id"`
-	Type            TransactionType `json:"type" db:"type"`
-	Amount          float64         `json:"amount" db:"amount"`
-	Description     string          `json:"description" db:"description"`
-	Status          string          `json:"status" db:"status"`
-	TransactionDate time.Time       `json:"transaction_date" db:"transaction_date"`
-	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	ID                int64           `json:"id" db:"id"`
+	UserID            int64           `json:"user_id" db:"user_id"`
+	FromAccountID     *int64          `json:"from_account_id,omitempty" db:"from_account_id"`
+	ToAccountID       *int64          `json:"to_account_id,omitempty" db:"to_account_id"`
+	Type              TransactionType `json:"type" db:"type"`
+	Amount            float64         `json:"amount" db:"amount"`
+	Description       string          `json:"description" db:"description"`
+	Status            string          `json:"status" db:"status"`
+	CreditID          *int64          `json:"credit_id,omitempty" db:"credit_id"`
+	PaymentScheduleID *int64          `json:"payment_schedule_id,omitempty" db:"payment_schedule_id"`
+	CardID            *int64          `json:"card_id,omitempty" db:"card_id"` // карта, которой оплачена операция
+	TransactionDate   time.Time       `json:"transaction_date" db:"transaction_date"`
+	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
 }
 
 type TransactionResponse struct {
-	ID              int64           `json:"id"`
-	Type            TransactionType `json:"type"`
-	Amount          float64         `json:"amount"`
-	Description     string          `json:"description"`
-	Status          string          `json:"status"`
-	TransactionDate time.Time       `json:"transaction_date"`
+	ID                int64           `json:"id"`
+	Type              TransactionType `json:"type"`
+	Amount            float64         `json:"amount"`
+	Description       string          `json:"description"`
+	Status            string          `json:"status"`
+	CreditID          *int64          `json:"credit_id,omitempty"`
+	PaymentScheduleID *int64          `json:"payment_schedule_id,omitempty"`
+	CardID            *int64          `json:"card_id,omitempty"`
+	TransactionDate   time.Time       `json:"transaction_date"`
 }
 
 type TransactionAnalytics struct {
@@ -51,11 +64,14 @@ type DailyTransactionSummary struct {
 
 func ToTransactionResponse(transaction Transaction) TransactionResponse {
 	return TransactionResponse{
-		ID:              transaction.ID,
-		Type:            transaction.Type,
-		Amount:          transaction.Amount,
-		Description:     transaction.Description,
-		Status:          transaction.Status,
-		TransactionDate: transaction.TransactionDate,
+		ID:                transaction.ID,
+		Type:              transaction.Type,
+		Amount:            transaction.Amount,
+		Description:       transaction.Description,
+		Status:            transaction.Status,
+		CreditID:          transaction.CreditID,
+		PaymentScheduleID: transaction.PaymentScheduleID,
+		CardID:            transaction.CardID,
+		TransactionDate:   transaction.TransactionDate,
 	}
 }
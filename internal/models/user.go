@@ -12,14 +12,24 @@ var (
 	ErrInvalidUsername = errors.New("username must be 3-20 characters long and contain only letters, numbers, and underscores")
 )
 
+type UserRole string
+
+const (
+	UserRoleCustomer UserRole = "CUSTOMER"
+	UserRoleOperator UserRole = "OPERATOR"
+)
+
 type User struct {
-	ID           int64     `json:"id" db:"id"`
-	Username     string    `json:"username" db:"username"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	FullName     string    `json:"full_name" db:"full_name"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID               int64      `json:"id" db:"id"`
+	Username         string     `json:"username" db:"username"`
+	Email            string     `json:"email" db:"email"`
+	PasswordHash     string     `json:"-" db:"password_hash"`
+	FullName         string     `json:"full_name" db:"full_name"`
+	Role             UserRole   `json:"role" db:"role"`
+	DeclaredIncome   float64    `json:"declared_income" db:"declared_income"`
+	IncomeDeclaredAt *time.Time `json:"income_declared_at,omitempty" db:"income_declared_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type UserRegistration struct {
@@ -35,11 +45,17 @@ type UserLogin struct {
 }
 
 type UserResponse struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	FullName  string    `json:"full_name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               int64      `json:"id"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	FullName         string     `json:"full_name"`
+	DeclaredIncome   float64    `json:"declared_income,omitempty"`
+	IncomeDeclaredAt *time.Time `json:"income_declared_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type DeclaredIncomeRequest struct {
+	DeclaredIncome float64 `json:"declared_income"`
 }
 
 func (u *UserRegistration) Validate() error {
@@ -62,10 +78,12 @@ func (u *UserRegistration) Validate() error {
 
 func ToUserResponse(user User) UserResponse {
 	return UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		CreatedAt: user.CreatedAt,
+		ID:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		FullName:         user.FullName,
+		DeclaredIncome:   user.DeclaredIncome,
+		IncomeDeclaredAt: user.IncomeDeclaredAt,
+		CreatedAt:        user.CreatedAt,
 	}
 }
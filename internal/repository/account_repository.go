@@ -15,6 +15,10 @@ type AccountRepository interface {
 	UpdateBalance(id int64, balance float64) error
 	BeginTx() (*sql.Tx, error)
 	UpdateBalanceTx(tx *sql.Tx, id int64, balance float64) error
+	AddToBalanceTx(tx *sql.Tx, id int64, amount float64) error
+	GetCreditLineAccounts() ([]models.Account, error)
+	UpdateCreditLimit(id int64, creditLimit float64) error
+	SetCreditLineSuspendedTx(tx *sql.Tx, id int64, suspended bool) error
 }
 
 type PostgresAccountRepository struct {
@@ -50,24 +54,34 @@ func (r *PostgresAccountRepository) Create(account models.Account) (int64, error
 	return id, nil
 }
 
-func (r *PostgresAccountRepository) GetByID(id int64) (models.Account, error) {
-	query := `
-		SELECT id, user_id, number, type, balance, created_at, updated_at
-		FROM accounts
-		WHERE id = $1
-	`
+const accountColumns = `id, user_id, number, type, balance, credit_limit, credit_line_suspended, created_at, updated_at`
 
+func scanAccount(row rowScanner) (models.Account, error) {
 	var account models.Account
-	err := r.db.QueryRow(query, id).Scan(
+
+	err := row.Scan(
 		&account.ID,
 		&account.UserID,
 		&account.Number,
 		&account.Type,
 		&account.Balance,
+		&account.CreditLimit,
+		&account.CreditLineSuspended,
 		&account.CreatedAt,
 		&account.UpdatedAt,
 	)
 
+	return account, err
+}
+
+func (r *PostgresAccountRepository) GetByID(id int64) (models.Account, error) {
+	query := `
+		SELECT ` + accountColumns + `
+		FROM accounts
+		WHERE id = $1
+	`
+
+	account, err := scanAccount(r.db.QueryRow(query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.Account{}, errors.New("account not found")
@@ -80,22 +94,12 @@ func (r *PostgresAccountRepository) GetByID(id int64) (models.Account, error) {
 
 func (r *PostgresAccountRepository) GetByNumber(number string) (models.Account, error) {
 	query := `
-		SELECT id, user_id, number, type, balance, created_at, updated_at
+		SELECT ` + accountColumns + `
 		FROM accounts
 		WHERE number = $1
 	`
 
-	var account models.Account
-	err := r.db.QueryRow(query, number).Scan(
-		&account.ID,
-		&account.UserID,
-		&account.Number,
-		&account.Type,
-		&account.Balance,
-		&account.CreatedAt,
-		&account.UpdatedAt,
-	)
-
+	account, err := scanAccount(r.db.QueryRow(query, number))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.Account{}, errors.New("account not found")
@@ -108,12 +112,28 @@ func (r *PostgresAccountRepository) GetByNumber(number string) (models.Account,
 
 func (r *PostgresAccountRepository) GetByUserID(userID int64) ([]models.Account, error) {
 	query := `
-		SELECT id, user_id, number, type, balance, created_at, updated_at
+		SELECT ` + accountColumns + `
 		FROM accounts
 		WHERE user_id = $1
 	`
 
-	rows, err := r.db.Query(query, userID)
+	return r.queryAccounts(query, userID)
+}
+
+// GetCreditLineAccounts возвращает счета CREDIT с кредитным лимитом или задолженностью
+func (r *PostgresAccountRepository) GetCreditLineAccounts() ([]models.Account, error) {
+	query := `
+		SELECT ` + accountColumns + `
+		FROM accounts
+		WHERE type = $1 AND (credit_limit > 0 OR balance < 0)
+		ORDER BY id
+	`
+
+	return r.queryAccounts(query, models.AccountTypeCredit)
+}
+
+func (r *PostgresAccountRepository) queryAccounts(query string, args ...interface{}) ([]models.Account, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -121,16 +141,8 @@ func (r *PostgresAccountRepository) GetByUserID(userID int64) ([]models.Account,
 
 	var accounts []models.Account
 	for rows.Next() {
-		var account models.Account
-		if err := rows.Scan(
-			&account.ID,
-			&account.UserID,
-			&account.Number,
-			&account.Type,
-			&account.Balance,
-			&account.CreatedAt,
-			&account.UpdatedAt,
-		); err != nil {
+		account, err := scanAccount(rows)
+		if err != nil {
 			return nil, err
 		}
 		accounts = append(accounts, account)
@@ -143,6 +155,28 @@ func (r *PostgresAccountRepository) GetByUserID(userID int64) ([]models.Account,
 	return accounts, nil
 }
 
+func (r *PostgresAccountRepository) UpdateCreditLimit(id int64, creditLimit float64) error {
+	query := `
+		UPDATE accounts
+		SET credit_limit = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(query, creditLimit, id)
+	return err
+}
+
+func (r *PostgresAccountRepository) SetCreditLineSuspendedTx(tx *sql.Tx, id int64, suspended bool) error {
+	query := `
+		UPDATE accounts
+		SET credit_line_suspended = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	_, err := tx.Exec(query, suspended, id)
+	return err
+}
+
 func (r *PostgresAccountRepository) UpdateBalance(id int64, balance float64) error {
 	query := `
 		UPDATE accounts
@@ -168,3 +202,16 @@ func (r *PostgresAccountRepository) UpdateBalanceTx(tx *sql.Tx, id int64, balanc
 	_, err := tx.Exec(query, balance, id)
 	return err
 }
+
+// AddToBalanceTx изменяет баланс на amount относительно значения в базе, а не прочитанного
+// ранее, поэтому параллельные изменения баланса не теряются
+func (r *PostgresAccountRepository) AddToBalanceTx(tx *sql.Tx, id int64, amount float64) error {
+	query := `
+		UPDATE accounts
+		SET balance = balance + $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	_, err := tx.Exec(query, amount, id)
+	return err
+}
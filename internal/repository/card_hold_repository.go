@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+type CardHoldRepository interface {
+	GetByRRN(cardID int64, rrn string) (models.CardHold, error)
+	GetActiveCreatedBefore(before time.Time) ([]models.CardHold, error)
+	GetActiveAmount(accountID int64) (float64, error)
+	BeginTx() (*sql.Tx, error)
+	CreateTx(tx *sql.Tx, hold models.CardHold) (int64, error)
+	CaptureTx(tx *sql.Tx, id int64, amount float64, transactionID int64) (bool, error)
+	UpdateStatusTx(tx *sql.Tx, id int64, from, to models.CardHoldStatus) (bool, error)
+}
+
+type PostgresCardHoldRepository struct {
+	db *sql.DB
+}
+
+func NewCardHoldRepository(db *sql.DB) CardHoldRepository {
+	return &PostgresCardHoldRepository{db: db}
+}
+
+const cardHoldColumns = `id, card_id, account_id, amount, rrn, auth_code, terminal_id, merchant_name, status, transaction_id, created_at, updated_at`
+
+func scanCardHold(row rowScanner) (models.CardHold, error) {
+	var hold models.CardHold
+	var transactionID sql.NullInt64
+
+	err := row.Scan(
+		&hold.ID,
+		&hold.CardID,
+		&hold.AccountID,
+		&hold.Amount,
+		&hold.RRN,
+		&hold.AuthCode,
+		&hold.TerminalID,
+		&hold.MerchantName,
+		&hold.Status,
+		&transactionID,
+		&hold.CreatedAt,
+		&hold.UpdatedAt,
+	)
+	if err != nil {
+		return models.CardHold{}, err
+	}
+
+	if transactionID.Valid {
+		hold.TransactionID = &transactionID.Int64
+	}
+
+	return hold, nil
+}
+
+func (r *PostgresCardHoldRepository) GetByRRN(cardID int64, rrn string) (models.CardHold, error) {
+	query := `SELECT ` + cardHoldColumns + ` FROM card_holds WHERE card_id = $1 AND rrn = $2`
+
+	hold, err := scanCardHold(r.db.QueryRow(query, cardID, rrn))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.CardHold{}, errors.New("card hold not found")
+		}
+		return models.CardHold{}, err
+	}
+
+	return hold, nil
+}
+
+// GetActiveCreatedBefore возвращает холды, которые не были закрыты или отменены
+// терминалом до момента before
+func (r *PostgresCardHoldRepository) GetActiveCreatedBefore(before time.Time) ([]models.CardHold, error) {
+	query := `SELECT ` + cardHoldColumns + ` FROM card_holds WHERE status = $1 AND created_at < $2 ORDER BY created_at`
+
+	rows, err := r.db.Query(query, models.CardHoldStatusActive, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []models.CardHold
+	for rows.Next() {
+		hold, err := scanCardHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+
+	return holds, rows.Err()
+}
+
+// GetActiveAmount возвращает сумму активных холдов по счету
+func (r *PostgresCardHoldRepository) GetActiveAmount(accountID int64) (float64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM card_holds WHERE account_id = $1 AND status = $2`
+
+	var amount float64
+	err := r.db.QueryRow(query, accountID, models.CardHoldStatusActive).Scan(&amount)
+	return amount, err
+}
+
+func (r *PostgresCardHoldRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
+
+func (r *PostgresCardHoldRepository) CreateTx(tx *sql.Tx, hold models.CardHold) (int64, error) {
+	query := `
+		INSERT INTO card_holds (card_id, account_id, amount, rrn, auth_code, terminal_id, merchant_name, status, transaction_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+
+	var id int64
+	err := tx.QueryRow(
+		query,
+		hold.CardID,
+		hold.AccountID,
+		hold.Amount,
+		hold.RRN,
+		hold.AuthCode,
+		hold.TerminalID,
+		hold.MerchantName,
+		hold.Status,
+		hold.TransactionID,
+		hold.CreatedAt,
+		hold.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// CaptureTx закрывает активный холд списанием: сохраняется фактически списанная сумма,
+// которая может быть меньше суммы авторизации и возвращается на счет при реверсале.
+// Возвращает false, если холд уже закрыт или отменен.
+func (r *PostgresCardHoldRepository) CaptureTx(tx *sql.Tx, id int64, amount float64, transactionID int64) (bool, error) {
+	query := `
+		UPDATE card_holds
+		SET status = $1, amount = $2, transaction_id = $3, updated_at = NOW()
+		WHERE id = $4 AND status = $5
+	`
+
+	result, err := tx.Exec(query, models.CardHoldStatusCaptured, amount, transactionID, id, models.CardHoldStatusActive)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// UpdateStatusTx меняет статус холда, только если текущий статус равен from. Возвращает
+// false, если холд успел изменить параллельный запрос.
+func (r *PostgresCardHoldRepository) UpdateStatusTx(tx *sql.Tx, id int64, from, to models.CardHoldStatus) (bool, error) {
+	query := `
+		UPDATE card_holds
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+	`
+
+	result, err := tx.Exec(query, to, id, from)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
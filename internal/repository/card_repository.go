@@ -10,9 +10,12 @@ import (
 type CardRepository interface {
 	Create(card models.Card) (int64, error)
 	GetByID(id int64) (models.Card, error)
+	GetByNumberHMAC(numberHMAC string) (models.Card, error)
 	GetByAccountID(accountID int64) ([]models.Card, error)
 	GetByUserID(userID int64) ([]models.Card, error)
 	UpdateStatus(id int64, isActive bool) error
+	BlockForCollections(userID int64) error
+	UnblockAfterCollections(userID int64) error
 }
 
 type PostgresCardRepository struct {
@@ -57,7 +60,7 @@ func (r *PostgresCardRepository) Create(card models.Card) (int64, error) {
 func (r *PostgresCardRepository) GetByID(id int64) (models.Card, error) {
 	query := `
 		SELECT id, account_id, user_id, number_encrypted, number_hmac, expiry_date_encrypted, 
-		       expiry_date_hmac, cvv_hash, type, is_active, created_at, updated_at
+		       expiry_date_hmac, cvv_hash, type, is_active, blocked_by_collections, created_at, updated_at
 		FROM cards
 		WHERE id = $1
 	`
@@ -74,6 +77,42 @@ func (r *PostgresCardRepository) GetByID(id int64) (models.Card, error) {
 		&card.CVV,
 		&card.Type,
 		&card.IsActive,
+		&card.BlockedByCollections,
+		&card.CreatedAt,
+		&card.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Card{}, errors.New("card not found")
+		}
+		return models.Card{}, err
+	}
+
+	return card, nil
+}
+
+func (r *PostgresCardRepository) GetByNumberHMAC(numberHMAC string) (models.Card, error) {
+	query := `
+		SELECT id, account_id, user_id, number_encrypted, number_hmac, expiry_date_encrypted, 
+		       expiry_date_hmac, cvv_hash, type, is_active, blocked_by_collections, created_at, updated_at
+		FROM cards
+		WHERE number_hmac = $1
+	`
+
+	var card models.Card
+	err := r.db.QueryRow(query, numberHMAC).Scan(
+		&card.ID,
+		&card.AccountID,
+		&card.UserID,
+		&card.Number,
+		&card.NumberHMAC,
+		&card.ExpiryDate,
+		&card.ExpiryHMAC,
+		&card.CVV,
+		&card.Type,
+		&card.IsActive,
+		&card.BlockedByCollections,
 		&card.CreatedAt,
 		&card.UpdatedAt,
 	)
@@ -91,7 +130,7 @@ func (r *PostgresCardRepository) GetByID(id int64) (models.Card, error) {
 func (r *PostgresCardRepository) GetByAccountID(accountID int64) ([]models.Card, error) {
 	query := `
 		SELECT id, account_id, user_id, number_encrypted, number_hmac, expiry_date_encrypted, 
-		       expiry_date_hmac, cvv_hash, type, is_active, created_at, updated_at
+		       expiry_date_hmac, cvv_hash, type, is_active, blocked_by_collections, created_at, updated_at
 		FROM cards
 		WHERE account_id = $1
 	`
@@ -116,6 +155,7 @@ func (r *PostgresCardRepository) GetByAccountID(accountID int64) ([]models.Card,
 			&card.CVV,
 			&card.Type,
 			&card.IsActive,
+			&card.BlockedByCollections,
 			&card.CreatedAt,
 			&card.UpdatedAt,
 		); err != nil {
@@ -134,7 +174,7 @@ func (r *PostgresCardRepository) GetByAccountID(accountID int64) ([]models.Card,
 func (r *PostgresCardRepository) GetByUserID(userID int64) ([]models.Card, error) {
 	query := `
 		SELECT id, account_id, user_id, number_encrypted, number_hmac, expiry_date_encrypted, 
-		       expiry_date_hmac, cvv_hash, type, is_active, created_at, updated_at
+		       expiry_date_hmac, cvv_hash, type, is_active, blocked_by_collections, created_at, updated_at
 		FROM cards
 		WHERE user_id = $1
 	`
@@ -159,6 +199,7 @@ func (r *PostgresCardRepository) GetByUserID(userID int64) ([]models.Card, error
 			&card.CVV,
 			&card.Type,
 			&card.IsActive,
+			&card.BlockedByCollections,
 			&card.CreatedAt,
 			&card.UpdatedAt,
 		); err != nil {
@@ -184,3 +225,30 @@ func (r *PostgresCardRepository) UpdateStatus(id int64, isActive bool) error {
 	_, err := r.db.Exec(query, isActive, id)
 	return err
 }
+
+// BlockForCollections блокирует карты заемщика из-за просроченной задолженности.
+// Блокировка хранится отдельно от is_active, чтобы после погашения просрочки карты
+// вернулись в состояние, выбранное владельцем.
+func (r *PostgresCardRepository) BlockForCollections(userID int64) error {
+	query := `
+		UPDATE cards
+		SET blocked_by_collections = TRUE, updated_at = NOW()
+		WHERE user_id = $1 AND blocked_by_collections = FALSE
+	`
+
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
+// UnblockAfterCollections снимает блокировку, установленную из-за просроченной задолженности.
+// Признак is_active не меняется: карты, отключенные владельцем, остаются отключенными.
+func (r *PostgresCardRepository) UnblockAfterCollections(userID int64) error {
+	query := `
+		UPDATE cards
+		SET blocked_by_collections = FALSE, updated_at = NOW()
+		WHERE user_id = $1 AND blocked_by_collections = TRUE
+	`
+
+	_, err := r.db.Exec(query, userID)
+	return err
+}
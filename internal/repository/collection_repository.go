@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+
+	"bank-service/internal/models"
+)
+
+type CollectionRepository interface {
+	Create(collectionCase models.CollectionCase) (int64, error)
+	GetOpenCases() ([]models.CollectionCase, error)
+	Update(collectionCase models.CollectionCase) error
+	AddAction(action models.CollectionCaseAction) error
+	GetOpenCasesByUserID(userID int64) ([]models.CollectionCase, error)
+}
+
+type PostgresCollectionRepository struct {
+	db *sql.DB
+}
+
+func NewCollectionRepository(db *sql.DB) CollectionRepository {
+	return &PostgresCollectionRepository{db: db}
+}
+
+const collectionCaseColumns = `id, credit_id, user_id, days_past_due, bucket, overdue_amount, overdue_since, status,
+		cards_blocked, escalated_bucket, created_at, updated_at, resolved_at`
+
+func scanCollectionCase(row rowScanner) (models.CollectionCase, error) {
+	var collectionCase models.CollectionCase
+	var resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&collectionCase.ID,
+		&collectionCase.CreditID,
+		&collectionCase.UserID,
+		&collectionCase.DaysPastDue,
+		&collectionCase.Bucket,
+		&collectionCase.OverdueAmount,
+		&collectionCase.OverdueSince,
+		&collectionCase.Status,
+		&collectionCase.CardsBlocked,
+		&collectionCase.EscalatedBucket,
+		&collectionCase.CreatedAt,
+		&collectionCase.UpdatedAt,
+		&resolvedAt,
+	)
+
+	if resolvedAt.Valid {
+		collectionCase.ResolvedAt = &resolvedAt.Time
+	}
+
+	return collectionCase, err
+}
+
+func (r *PostgresCollectionRepository) Create(collectionCase models.CollectionCase) (int64, error) {
+	query := `
+		INSERT INTO credit_collection_cases (credit_id, user_id, days_past_due, bucket, overdue_amount, overdue_since,
+			status, cards_blocked, escalated_bucket, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+
+	var id int64
+	err := r.db.QueryRow(
+		query,
+		collectionCase.CreditID,
+		collectionCase.UserID,
+		collectionCase.DaysPastDue,
+		collectionCase.Bucket,
+		collectionCase.OverdueAmount,
+		collectionCase.OverdueSince,
+		collectionCase.Status,
+		collectionCase.CardsBlocked,
+		collectionCase.EscalatedBucket,
+		collectionCase.CreatedAt,
+		collectionCase.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetOpenCases возвращает открытые дела, начиная с наибольшей просрочки
+func (r *PostgresCollectionRepository) GetOpenCases() ([]models.CollectionCase, error) {
+	query := `
+		SELECT ` + collectionCaseColumns + `
+		FROM credit_collection_cases
+		WHERE status = $1
+		ORDER BY days_past_due DESC, id
+	`
+
+	return r.queryCases(query, models.CollectionCaseOpen)
+}
+
+func (r *PostgresCollectionRepository) Update(collectionCase models.CollectionCase) error {
+	query := `
+		UPDATE credit_collection_cases
+		SET days_past_due = $1, bucket = $2, overdue_amount = $3, status = $4, cards_blocked = $5,
+			escalated_bucket = $6, resolved_at = $7, updated_at = NOW()
+		WHERE id = $8
+	`
+
+	_, err := r.db.Exec(
+		query,
+		collectionCase.DaysPastDue,
+		collectionCase.Bucket,
+		collectionCase.OverdueAmount,
+		collectionCase.Status,
+		collectionCase.CardsBlocked,
+		collectionCase.EscalatedBucket,
+		collectionCase.ResolvedAt,
+		collectionCase.ID,
+	)
+	return err
+}
+
+func (r *PostgresCollectionRepository) AddAction(action models.CollectionCaseAction) error {
+	query := `
+		INSERT INTO credit_collection_actions (case_id, bucket, action, details, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.Exec(query, action.CaseID, action.Bucket, action.Action, action.Details, action.CreatedAt)
+	return err
+}
+
+func (r *PostgresCollectionRepository) GetOpenCasesByUserID(userID int64) ([]models.CollectionCase, error) {
+	query := `
+		SELECT ` + collectionCaseColumns + `
+		FROM credit_collection_cases
+		WHERE user_id = $1 AND status = $2
+		ORDER BY days_past_due DESC, id
+	`
+
+	return r.queryCases(query, userID, models.CollectionCaseOpen)
+}
+
+func (r *PostgresCollectionRepository) queryCases(query string, args ...interface{}) ([]models.CollectionCase, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cases []models.CollectionCase
+	for rows.Next() {
+		collectionCase, err := scanCollectionCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, collectionCase)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cases, nil
+}
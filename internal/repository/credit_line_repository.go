@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+type CreditLineRepository interface {
+	CreateStatementTx(tx *sql.Tx, statement models.CreditLineStatement) (int64, error)
+	GetLastStatement(accountID int64) (*models.CreditLineStatement, error)
+	GetStatementsByAccountID(accountID int64) ([]models.CreditLineStatement, error)
+	GetUnsettledStatements() ([]models.CreditLineStatement, error)
+	UpdateStatementTx(tx *sql.Tx, statement models.CreditLineStatement) error
+	AddAccrual(accrual models.CreditLineAccrual) error
+	GetLastAccrualDate(accountID int64) (*time.Time, error)
+	SumAccruals(accountID int64, from, to time.Time) (float64, error)
+	BeginTx() (*sql.Tx, error)
+}
+
+type PostgresCreditLineRepository struct {
+	db *sql.DB
+}
+
+func NewCreditLineRepository(db *sql.DB) CreditLineRepository {
+	return &PostgresCreditLineRepository{db: db}
+}
+
+const statementColumns = `id, account_id, period_start, period_end, opening_balance, closing_balance, debits, credits,
+		accrued_interest, interest, debt, minimum_payment, due_date, paid_amount, late_fee, status, created_at, updated_at`
+
+func scanStatement(row rowScanner) (models.CreditLineStatement, error) {
+	var statement models.CreditLineStatement
+
+	err := row.Scan(
+		&statement.ID,
+		&statement.AccountID,
+		&statement.PeriodStart,
+		&statement.PeriodEnd,
+		&statement.OpeningBalance,
+		&statement.ClosingBalance,
+		&statement.Debits,
+		&statement.Credits,
+		&statement.AccruedInterest,
+		&statement.Interest,
+		&statement.Debt,
+		&statement.MinimumPayment,
+		&statement.DueDate,
+		&statement.PaidAmount,
+		&statement.LateFee,
+		&statement.Status,
+		&statement.CreatedAt,
+		&statement.UpdatedAt,
+	)
+
+	return statement, err
+}
+
+func (r *PostgresCreditLineRepository) CreateStatementTx(tx *sql.Tx, statement models.CreditLineStatement) (int64, error) {
+	query := `
+		INSERT INTO credit_line_statements (account_id, period_start, period_end, opening_balance, closing_balance, debits, credits,
+			accrued_interest, interest, debt, minimum_payment, due_date, paid_amount, late_fee, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING id
+	`
+
+	var id int64
+	err := tx.QueryRow(
+		query,
+		statement.AccountID,
+		statement.PeriodStart,
+		statement.PeriodEnd,
+		statement.OpeningBalance,
+		statement.ClosingBalance,
+		statement.Debits,
+		statement.Credits,
+		statement.AccruedInterest,
+		statement.Interest,
+		statement.Debt,
+		statement.MinimumPayment,
+		statement.DueDate,
+		statement.PaidAmount,
+		statement.LateFee,
+		statement.Status,
+		statement.CreatedAt,
+		statement.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetLastStatement возвращает последнюю выписку по счету или nil, если выписок еще не было
+func (r *PostgresCreditLineRepository) GetLastStatement(accountID int64) (*models.CreditLineStatement, error) {
+	query := `
+		SELECT ` + statementColumns + `
+		FROM credit_line_statements
+		WHERE account_id = $1
+		ORDER BY period_end DESC
+		LIMIT 1
+	`
+
+	statement, err := scanStatement(r.db.QueryRow(query, accountID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &statement, nil
+}
+
+func (r *PostgresCreditLineRepository) GetStatementsByAccountID(accountID int64) ([]models.CreditLineStatement, error) {
+	query := `
+		SELECT ` + statementColumns + `
+		FROM credit_line_statements
+		WHERE account_id = $1
+		ORDER BY period_end DESC
+	`
+
+	return r.queryStatements(query, accountID)
+}
+
+// GetUnsettledStatements возвращает выписки, по которым еще ожидается или просрочен минимальный платеж
+func (r *PostgresCreditLineRepository) GetUnsettledStatements() ([]models.CreditLineStatement, error) {
+	query := `
+		SELECT ` + statementColumns + `
+		FROM credit_line_statements
+		WHERE status IN ($1, $2)
+		ORDER BY account_id, period_end
+	`
+
+	return r.queryStatements(query, models.StatementStatusOpen, models.StatementStatusOverdue)
+}
+
+func (r *PostgresCreditLineRepository) queryStatements(query string, args ...interface{}) ([]models.CreditLineStatement, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []models.CreditLineStatement
+	for rows.Next() {
+		statement, err := scanStatement(rows)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+func (r *PostgresCreditLineRepository) UpdateStatementTx(tx *sql.Tx, statement models.CreditLineStatement) error {
+	query := `
+		UPDATE credit_line_statements
+		SET interest = $1, paid_amount = $2, late_fee = $3, status = $4, updated_at = NOW()
+		WHERE id = $5
+	`
+
+	_, err := tx.Exec(query, statement.Interest, statement.PaidAmount, statement.LateFee, statement.Status, statement.ID)
+	return err
+}
+
+// AddAccrual сохраняет начисление за день. Повторное начисление за тот же день игнорируется.
+func (r *PostgresCreditLineRepository) AddAccrual(accrual models.CreditLineAccrual) error {
+	query := `
+		INSERT INTO credit_line_accruals (account_id, accrual_date, used_amount, interest_rate, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (account_id, accrual_date) DO NOTHING
+	`
+
+	_, err := r.db.Exec(
+		query,
+		accrual.AccountID,
+		accrual.AccrualDate,
+		accrual.UsedAmount,
+		accrual.InterestRate,
+		accrual.Amount,
+		accrual.CreatedAt,
+	)
+	return err
+}
+
+// GetLastAccrualDate возвращает дату последнего начисления процентов или nil, если начислений не было
+func (r *PostgresCreditLineRepository) GetLastAccrualDate(accountID int64) (*time.Time, error) {
+	query := `
+		SELECT MAX(accrual_date)
+		FROM credit_line_accruals
+		WHERE account_id = $1
+	`
+
+	var last sql.NullTime
+	if err := r.db.QueryRow(query, accountID).Scan(&last); err != nil {
+		return nil, err
+	}
+
+	if !last.Valid {
+		return nil, nil
+	}
+
+	return &last.Time, nil
+}
+
+// SumAccruals возвращает проценты, начисленные за дни периода [from, to)
+func (r *PostgresCreditLineRepository) SumAccruals(accountID int64, from, to time.Time) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM credit_line_accruals
+		WHERE account_id = $1 AND accrual_date >= $2 AND accrual_date < $3
+	`
+
+	var sum float64
+	err := r.db.QueryRow(query, accountID, from, to).Scan(&sum)
+	return sum, err
+}
+
+func (r *PostgresCreditLineRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"bank-service/internal/models"
+)
+
+type CreditProductRepository interface {
+	Create(product models.CreditProduct) (int64, error)
+	GetByID(id int64) (models.CreditProduct, error)
+	GetByCode(code string) (models.CreditProduct, error)
+	GetAll(activeOnly bool) ([]models.CreditProduct, error)
+	Update(product models.CreditProduct) error
+}
+
+type PostgresCreditProductRepository struct {
+	db *sql.DB
+}
+
+func NewCreditProductRepository(db *sql.DB) CreditProductRepository {
+	return &PostgresCreditProductRepository{db: db}
+}
+
+const creditProductColumns = `id, code, name, description, rate_spread, is_floating, day_count, min_amount, max_amount, min_term, max_term,
+		issuance_fee_rate, issuance_fee_fixed, min_income, max_active_credits, is_active, created_at, updated_at`
+
+func scanCreditProduct(row rowScanner) (models.CreditProduct, error) {
+	var product models.CreditProduct
+
+	err := row.Scan(
+		&product.ID,
+		&product.Code,
+		&product.Name,
+		&product.Description,
+		&product.RateSpread,
+		&product.IsFloating,
+		&product.DayCount,
+		&product.MinAmount,
+		&product.MaxAmount,
+		&product.MinTerm,
+		&product.MaxTerm,
+		&product.IssuanceFeeRate,
+		&product.IssuanceFeeFixed,
+		&product.MinIncome,
+		&product.MaxActiveCredits,
+		&product.IsActive,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+
+	return product, err
+}
+
+func (r *PostgresCreditProductRepository) Create(product models.CreditProduct) (int64, error) {
+	query := `
+		INSERT INTO credit_products (code, name, description, rate_spread, is_floating, day_count, min_amount, max_amount, min_term, max_term,
+			issuance_fee_rate, issuance_fee_fixed, min_income, max_active_credits, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING id
+	`
+
+	var id int64
+	err := r.db.QueryRow(
+		query,
+		product.Code,
+		product.Name,
+		product.Description,
+		product.RateSpread,
+		product.IsFloating,
+		product.DayCount,
+		product.MinAmount,
+		product.MaxAmount,
+		product.MinTerm,
+		product.MaxTerm,
+		product.IssuanceFeeRate,
+		product.IssuanceFeeFixed,
+		product.MinIncome,
+		product.MaxActiveCredits,
+		product.IsActive,
+		product.CreatedAt,
+		product.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *PostgresCreditProductRepository) GetByID(id int64) (models.CreditProduct, error) {
+	query := `
+		SELECT ` + creditProductColumns + `
+		FROM credit_products
+		WHERE id = $1
+	`
+
+	product, err := scanCreditProduct(r.db.QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.CreditProduct{}, errors.New("credit product not found")
+		}
+		return models.CreditProduct{}, err
+	}
+
+	return product, nil
+}
+
+func (r *PostgresCreditProductRepository) GetByCode(code string) (models.CreditProduct, error) {
+	query := `
+		SELECT ` + creditProductColumns + `
+		FROM credit_products
+		WHERE code = $1
+	`
+
+	product, err := scanCreditProduct(r.db.QueryRow(query, code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.CreditProduct{}, errors.New("credit product not found")
+		}
+		return models.CreditProduct{}, err
+	}
+
+	return product, nil
+}
+
+func (r *PostgresCreditProductRepository) GetAll(activeOnly bool) ([]models.CreditProduct, error) {
+	query := `
+		SELECT ` + creditProductColumns + `
+		FROM credit_products
+		WHERE is_active OR NOT $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(query, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []models.CreditProduct
+	for rows.Next() {
+		product, err := scanCreditProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *PostgresCreditProductRepository) Update(product models.CreditProduct) error {
+	query := `
+		UPDATE credit_products
+		SET code = $1, name = $2, description = $3, rate_spread = $4, is_floating = $5, day_count = $6, min_amount = $7,
+			max_amount = $8, min_term = $9, max_term = $10, issuance_fee_rate = $11, issuance_fee_fixed = $12,
+			min_income = $13, max_active_credits = $14, is_active = $15, updated_at = NOW()
+		WHERE id = $16
+	`
+
+	_, err := r.db.Exec(
+		query,
+		product.Code,
+		product.Name,
+		product.Description,
+		product.RateSpread,
+		product.IsFloating,
+		product.DayCount,
+		product.MinAmount,
+		product.MaxAmount,
+		product.MinTerm,
+		product.MaxTerm,
+		product.IssuanceFeeRate,
+		product.IssuanceFeeFixed,
+		product.MinIncome,
+		product.MaxActiveCredits,
+		product.IsActive,
+		product.ID,
+	)
+	return err
+}
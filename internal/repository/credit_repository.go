@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"time"
 
 	"bank-service/internal/models"
 )
@@ -11,10 +12,25 @@ type CreditRepository interface {
 	Create(credit models.Credit) (int64, error)
 	GetByID(id int64) (models.Credit, error)
 	GetByUserID(userID int64) ([]models.Credit, error)
+	GetByStatus(status models.CreditStatus) ([]models.Credit, error)
 	GetActiveCredits() ([]models.Credit, error)
-	UpdateStatus(id int64, status models.CreditStatus) error
+	GetWithoutFullCost() ([]models.Credit, error)
+	UpdateFullCost(id int64, rate, amount float64) error
+	GetOutstandingDebt(userID int64) (float64, error)
+	CountOverdueEvents(userID int64) (int, error)
+	UpdateStatusTx(tx *sql.Tx, id int64, status models.CreditStatus) error
+	AddStatusHistoryTx(tx *sql.Tx, entry models.CreditStatusHistory) error
+	GetStatusHistory(creditID int64) ([]models.CreditStatusHistory, error)
+	AddRateHistoryTx(tx *sql.Tx, entry models.CreditRateHistory) error
+	GetRateHistory(creditID int64) ([]models.CreditRateHistory, error)
+	AddInterestAccrual(accrual models.CreditInterestAccrual) error
+	GetLastAccrualDate(creditID int64) (*time.Time, error)
+	GetInterestAccruals(creditID int64, from, to time.Time) ([]models.CreditInterestAccrual, error)
+	AddRestructuringTx(tx *sql.Tx, restructuring models.CreditRestructuring) error
+	GetRestructurings(creditID int64) ([]models.CreditRestructuring, error)
 	BeginTx() (*sql.Tx, error)
 	CreateTx(tx *sql.Tx, credit models.Credit) (int64, error)
+	UpdateTx(tx *sql.Tx, credit models.Credit) error
 }
 
 type PostgresCreditRepository struct {
@@ -25,61 +41,82 @@ func NewCreditRepository(db *sql.DB) CreditRepository {
 	return &PostgresCreditRepository{db: db}
 }
 
-func (r *PostgresCreditRepository) Create(credit models.Credit) (int64, error) {
-	query := `
-		INSERT INTO credits (user_id, account_id, amount, term, interest_rate, monthly_payment, total_payment, status, start_date, end_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING id
-	`
-
-	var id int64
-	err := r.db.QueryRow(
-		query,
-		credit.UserID,
-		credit.AccountID,
-		credit.Amount,
-		credit.Term,
-		credit.InterestRate,
-		credit.MonthlyPayment,
-		credit.TotalPayment,
-		credit.Status,
-		credit.StartDate,
-		credit.EndDate,
-		credit.CreatedAt,
-		credit.UpdatedAt,
-	).Scan(&id)
+const creditColumns = `id, user_id, account_id, product_id, amount, issuance_fee, term, interest_rate, is_floating, key_rate, key_rate_stale, rate_spread, day_count,
+		repayment_type, monthly_payment, total_payment, full_cost_rate, full_cost_amount, status, declared_income, score, decision_reason,
+		rate_adjustment, decided_at, start_date, end_date, created_at, updated_at`
 
-	if err != nil {
-		return 0, err
-	}
-
-	return id, nil
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-func (r *PostgresCreditRepository) GetByID(id int64) (models.Credit, error) {
-	query := `
-		SELECT id, user_id, account_id, amount, term, interest_rate, monthly_payment, total_payment, status, start_date, end_date, created_at, updated_at
-		FROM credits
-		WHERE id = $1
-	`
-
+func scanCredit(row rowScanner) (models.Credit, error) {
 	var credit models.Credit
-	err := r.db.QueryRow(query, id).Scan(
+	var decidedAt sql.NullTime
+
+	err := row.Scan(
 		&credit.ID,
 		&credit.UserID,
 		&credit.AccountID,
+		&credit.ProductID,
 		&credit.Amount,
+		&credit.IssuanceFee,
 		&credit.Term,
 		&credit.InterestRate,
+		&credit.IsFloating,
+		&credit.KeyRate,
+		&credit.KeyRateStale,
+		&credit.RateSpread,
+		&credit.DayCount,
+		&credit.RepaymentType,
 		&credit.MonthlyPayment,
 		&credit.TotalPayment,
+		&credit.FullCostRate,
+		&credit.FullCostAmount,
 		&credit.Status,
+		&credit.DeclaredIncome,
+		&credit.Score,
+		&credit.DecisionReason,
+		&credit.RateAdjustment,
+		&decidedAt,
 		&credit.StartDate,
 		&credit.EndDate,
 		&credit.CreatedAt,
 		&credit.UpdatedAt,
 	)
+	if err != nil {
+		return models.Credit{}, err
+	}
+
+	if decidedAt.Valid {
+		credit.DecidedAt = &decidedAt.Time
+	}
+
+	return credit, nil
+}
+
+func (r *PostgresCreditRepository) Create(credit models.Credit) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
 
+	id, err := r.CreateTx(tx, credit)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (r *PostgresCreditRepository) GetByID(id int64) (models.Credit, error) {
+	query := `
+		SELECT ` + creditColumns + `
+		FROM credits
+		WHERE id = $1
+	`
+
+	credit, err := scanCredit(r.db.QueryRow(query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.Credit{}, errors.New("credit not found")
@@ -92,12 +129,60 @@ func (r *PostgresCreditRepository) GetByID(id int64) (models.Credit, error) {
 
 func (r *PostgresCreditRepository) GetByUserID(userID int64) ([]models.Credit, error) {
 	query := `
-		SELECT id, user_id, account_id, amount, term, interest_rate, monthly_payment, total_payment, status, start_date, end_date, created_at, updated_at
+		SELECT ` + creditColumns + `
 		FROM credits
 		WHERE user_id = $1
 	`
 
-	rows, err := r.db.Query(query, userID)
+	return r.queryCredits(query, userID)
+}
+
+func (r *PostgresCreditRepository) GetByStatus(status models.CreditStatus) ([]models.Credit, error) {
+	query := `
+		SELECT ` + creditColumns + `
+		FROM credits
+		WHERE status = $1
+		ORDER BY created_at
+	`
+
+	return r.queryCredits(query, status)
+}
+
+func (r *PostgresCreditRepository) GetActiveCredits() ([]models.Credit, error) {
+	query := `
+		SELECT ` + creditColumns + `
+		FROM credits
+		WHERE status IN ($1, $2)
+	`
+
+	return r.queryCredits(query, models.CreditStatusActive, models.CreditStatusOverdue)
+}
+
+// GetWithoutFullCost возвращает кредиты, для которых не рассчитана ПСК
+func (r *PostgresCreditRepository) GetWithoutFullCost() ([]models.Credit, error) {
+	query := `
+		SELECT ` + creditColumns + `
+		FROM credits
+		WHERE full_cost_rate = 0 AND full_cost_amount = 0
+		ORDER BY id
+	`
+
+	return r.queryCredits(query)
+}
+
+func (r *PostgresCreditRepository) UpdateFullCost(id int64, rate, amount float64) error {
+	query := `
+		UPDATE credits
+		SET full_cost_rate = $1, full_cost_amount = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	_, err := r.db.Exec(query, rate, amount, id)
+	return err
+}
+
+func (r *PostgresCreditRepository) queryCredits(query string, args ...interface{}) ([]models.Credit, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -105,22 +190,8 @@ func (r *PostgresCreditRepository) GetByUserID(userID int64) ([]models.Credit, e
 
 	var credits []models.Credit
 	for rows.Next() {
-		var credit models.Credit
-		if err := rows.Scan(
-			&credit.ID,
-			&credit.UserID,
-			&credit.AccountID,
-			&credit.Amount,
-			&credit.Term,
-			&credit.InterestRate,
-			&credit.MonthlyPayment,
-			&credit.TotalPayment,
-			&credit.Status,
-			&credit.StartDate,
-			&credit.EndDate,
-			&credit.CreatedAt,
-			&credit.UpdatedAt,
-		); err != nil {
+		credit, err := scanCredit(rows)
+		if err != nil {
 			return nil, err
 		}
 		credits = append(credits, credit)
@@ -133,68 +204,321 @@ func (r *PostgresCreditRepository) GetByUserID(userID int64) ([]models.Credit, e
 	return credits, nil
 }
 
-func (r *PostgresCreditRepository) GetActiveCredits() ([]models.Credit, error) {
+// GetOutstandingDebt возвращает сумму непогашенных платежей по всем кредитам пользователя
+func (r *PostgresCreditRepository) GetOutstandingDebt(userID int64) (float64, error) {
 	query := `
-		SELECT id, user_id, account_id, amount, term, interest_rate, monthly_payment, total_payment, status, start_date, end_date, created_at, updated_at
-		FROM credits
-		WHERE status IN ($1, $2)
+		SELECT COALESCE(SUM(ps.amount - ps.paid_amount), 0)
+		FROM payment_schedules ps
+		JOIN credits c ON c.id = ps.credit_id
+		WHERE c.user_id = $1 AND ps.status IN ($2, $3)
+	`
+
+	var debt float64
+	err := r.db.QueryRow(query, userID, models.PaymentStatusPending, models.PaymentStatusOverdue).Scan(&debt)
+	return debt, err
+}
+
+// CountOverdueEvents возвращает число просрочек пользователя за всю историю.
+// Каждая просрочка фиксируется штрафом, поэтому считаются штрафы типа FINE.
+func (r *PostgresCreditRepository) CountOverdueEvents(userID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM credit_penalties p
+		JOIN credits c ON c.id = p.credit_id
+		WHERE c.user_id = $1 AND p.type = $2
 	`
 
-	rows, err := r.db.Query(query, models.CreditStatusActive, models.CreditStatusOverdue)
+	var count int
+	err := r.db.QueryRow(query, userID, models.PenaltyTypeFine).Scan(&count)
+	return count, err
+}
+
+func (r *PostgresCreditRepository) UpdateStatusTx(tx *sql.Tx, id int64, status models.CreditStatus) error {
+	query := `
+		UPDATE credits
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	_, err := tx.Exec(query, status, id)
+	return err
+}
+
+func (r *PostgresCreditRepository) AddStatusHistoryTx(tx *sql.Tx, entry models.CreditStatusHistory) error {
+	query := `
+		INSERT INTO credit_status_history (credit_id, from_status, to_status, reason, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := tx.Exec(query, entry.CreditID, entry.FromStatus, entry.ToStatus, entry.Reason, entry.ChangedAt)
+	return err
+}
+
+func (r *PostgresCreditRepository) GetStatusHistory(creditID int64) ([]models.CreditStatusHistory, error) {
+	query := `
+		SELECT id, credit_id, from_status, to_status, reason, changed_at
+		FROM credit_status_history
+		WHERE credit_id = $1
+		ORDER BY changed_at, id
+	`
+
+	rows, err := r.db.Query(query, creditID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var credits []models.Credit
+	var history []models.CreditStatusHistory
 	for rows.Next() {
-		var credit models.Credit
+		var entry models.CreditStatusHistory
+		var fromStatus sql.NullString
+
 		if err := rows.Scan(
-			&credit.ID,
-			&credit.UserID,
-			&credit.AccountID,
-			&credit.Amount,
-			&credit.Term,
-			&credit.InterestRate,
-			&credit.MonthlyPayment,
-			&credit.TotalPayment,
-			&credit.Status,
-			&credit.StartDate,
-			&credit.EndDate,
-			&credit.CreatedAt,
-			&credit.UpdatedAt,
+			&entry.ID,
+			&entry.CreditID,
+			&fromStatus,
+			&entry.ToStatus,
+			&entry.Reason,
+			&entry.ChangedAt,
 		); err != nil {
 			return nil, err
 		}
-		credits = append(credits, credit)
+
+		if fromStatus.Valid {
+			status := models.CreditStatus(fromStatus.String)
+			entry.FromStatus = &status
+		}
+
+		history = append(history, entry)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return credits, nil
+	return history, nil
 }
 
-func (r *PostgresCreditRepository) UpdateStatus(id int64, status models.CreditStatus) error {
+func (r *PostgresCreditRepository) AddRateHistoryTx(tx *sql.Tx, entry models.CreditRateHistory) error {
 	query := `
-		UPDATE credits
-		SET status = $1, updated_at = NOW()
-		WHERE id = $2
+		INSERT INTO credit_rate_history (credit_id, key_rate, interest_rate, monthly_payment, effective_from, reason, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := tx.Exec(
+		query,
+		entry.CreditID,
+		entry.KeyRate,
+		entry.InterestRate,
+		entry.MonthlyPayment,
+		entry.EffectiveFrom,
+		entry.Reason,
+		entry.ChangedAt,
+	)
+	return err
+}
+
+func (r *PostgresCreditRepository) GetRateHistory(creditID int64) ([]models.CreditRateHistory, error) {
+	query := `
+		SELECT id, credit_id, key_rate, interest_rate, monthly_payment, effective_from, reason, changed_at
+		FROM credit_rate_history
+		WHERE credit_id = $1
+		ORDER BY changed_at, id
+	`
+
+	rows, err := r.db.Query(query, creditID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []models.CreditRateHistory
+	for rows.Next() {
+		var entry models.CreditRateHistory
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.CreditID,
+			&entry.KeyRate,
+			&entry.InterestRate,
+			&entry.MonthlyPayment,
+			&entry.EffectiveFrom,
+			&entry.Reason,
+			&entry.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// AddInterestAccrual сохраняет начисление за день. Повторное начисление за тот же день игнорируется.
+func (r *PostgresCreditRepository) AddInterestAccrual(accrual models.CreditInterestAccrual) error {
+	query := `
+		INSERT INTO credit_interest_accruals (credit_id, accrual_date, principal, interest_rate, day_count, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (credit_id, accrual_date) DO NOTHING
+	`
+
+	_, err := r.db.Exec(
+		query,
+		accrual.CreditID,
+		accrual.AccrualDate,
+		accrual.Principal,
+		accrual.InterestRate,
+		accrual.DayCount,
+		accrual.Amount,
+		accrual.CreatedAt,
+	)
+	return err
+}
+
+// GetLastAccrualDate возвращает дату последнего начисления процентов или nil, если начислений не было
+func (r *PostgresCreditRepository) GetLastAccrualDate(creditID int64) (*time.Time, error) {
+	query := `
+		SELECT MAX(accrual_date)
+		FROM credit_interest_accruals
+		WHERE credit_id = $1
+	`
+
+	var last sql.NullTime
+	if err := r.db.QueryRow(query, creditID).Scan(&last); err != nil {
+		return nil, err
+	}
+
+	if !last.Valid {
+		return nil, nil
+	}
+
+	return &last.Time, nil
+}
+
+func (r *PostgresCreditRepository) GetInterestAccruals(creditID int64, from, to time.Time) ([]models.CreditInterestAccrual, error) {
+	query := `
+		SELECT id, credit_id, accrual_date, principal, interest_rate, day_count, amount, created_at
+		FROM credit_interest_accruals
+		WHERE credit_id = $1 AND accrual_date > $2 AND accrual_date <= $3
+		ORDER BY accrual_date
+	`
+
+	rows, err := r.db.Query(query, creditID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accruals []models.CreditInterestAccrual
+	for rows.Next() {
+		var accrual models.CreditInterestAccrual
+		if err := rows.Scan(
+			&accrual.ID,
+			&accrual.CreditID,
+			&accrual.AccrualDate,
+			&accrual.Principal,
+			&accrual.InterestRate,
+			&accrual.DayCount,
+			&accrual.Amount,
+			&accrual.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		accruals = append(accruals, accrual)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accruals, nil
+}
+
+func (r *PostgresCreditRepository) AddRestructuringTx(tx *sql.Tx, restructuring models.CreditRestructuring) error {
+	query := `
+		INSERT INTO credit_restructurings (credit_id, type, months, principal_before, capitalized_interest, principal_after,
+			term_before, term_after, monthly_payment_before, monthly_payment_after, first_payment_date, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
-	_, err := r.db.Exec(query, status, id)
+	_, err := tx.Exec(
+		query,
+		restructuring.CreditID,
+		restructuring.Type,
+		restructuring.Months,
+		restructuring.PrincipalBefore,
+		restructuring.CapitalizedInterest,
+		restructuring.PrincipalAfter,
+		restructuring.TermBefore,
+		restructuring.TermAfter,
+		restructuring.MonthlyPaymentBefore,
+		restructuring.MonthlyPaymentAfter,
+		restructuring.FirstPaymentDate,
+		restructuring.Reason,
+		restructuring.CreatedAt,
+	)
 	return err
 }
 
+func (r *PostgresCreditRepository) GetRestructurings(creditID int64) ([]models.CreditRestructuring, error) {
+	query := `
+		SELECT id, credit_id, type, months, principal_before, capitalized_interest, principal_after, term_before, term_after,
+			monthly_payment_before, monthly_payment_after, first_payment_date, reason, created_at
+		FROM credit_restructurings
+		WHERE credit_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(query, creditID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var restructurings []models.CreditRestructuring
+	for rows.Next() {
+		var restructuring models.CreditRestructuring
+		if err := rows.Scan(
+			&restructuring.ID,
+			&restructuring.CreditID,
+			&restructuring.Type,
+			&restructuring.Months,
+			&restructuring.PrincipalBefore,
+			&restructuring.CapitalizedInterest,
+			&restructuring.PrincipalAfter,
+			&restructuring.TermBefore,
+			&restructuring.TermAfter,
+			&restructuring.MonthlyPaymentBefore,
+			&restructuring.MonthlyPaymentAfter,
+			&restructuring.FirstPaymentDate,
+			&restructuring.Reason,
+			&restructuring.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		restructurings = append(restructurings, restructuring)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return restructurings, nil
+}
+
 func (r *PostgresCreditRepository) BeginTx() (*sql.Tx, error) {
 	return r.db.Begin()
 }
 
 func (r *PostgresCreditRepository) CreateTx(tx *sql.Tx, credit models.Credit) (int64, error) {
 	query := `
-		INSERT INTO credits (user_id, account_id, amount, term, interest_rate, monthly_payment, total_payment, status, start_date, end_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO credits (user_id, account_id, product_id, amount, issuance_fee, term, interest_rate, is_floating, key_rate, key_rate_stale, rate_spread, day_count,
+			repayment_type, monthly_payment, total_payment, full_cost_rate, full_cost_amount, status, declared_income, score, decision_reason,
+			rate_adjustment, decided_at, start_date, end_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
 		RETURNING id
 	`
 
@@ -203,12 +527,27 @@ func (r *PostgresCreditRepository) CreateTx(tx *sql.Tx, credit models.Credit) (i
 		query,
 		credit.UserID,
 		credit.AccountID,
+		credit.ProductID,
 		credit.Amount,
+		credit.IssuanceFee,
 		credit.Term,
 		credit.InterestRate,
+		credit.IsFloating,
+		credit.KeyRate,
+		credit.KeyRateStale,
+		credit.RateSpread,
+		credit.DayCount,
+		credit.RepaymentType,
 		credit.MonthlyPayment,
 		credit.TotalPayment,
+		credit.FullCostRate,
+		credit.FullCostAmount,
 		credit.Status,
+		credit.DeclaredIncome,
+		credit.Score,
+		credit.DecisionReason,
+		credit.RateAdjustment,
+		credit.DecidedAt,
 		credit.StartDate,
 		credit.EndDate,
 		credit.CreatedAt,
@@ -221,3 +560,34 @@ func (r *PostgresCreditRepository) CreateTx(tx *sql.Tx, credit models.Credit) (i
 
 	return id, nil
 }
+
+func (r *PostgresCreditRepository) UpdateTx(tx *sql.Tx, credit models.Credit) error {
+	query := `
+		UPDATE credits
+		SET term = $1, interest_rate = $2, monthly_payment = $3, total_payment = $4, status = $5,
+			score = $6, decision_reason = $7, rate_adjustment = $8, decided_at = $9,
+			start_date = $10, end_date = $11, full_cost_rate = $12, full_cost_amount = $13, key_rate = $14, key_rate_stale = $15, updated_at = NOW()
+		WHERE id = $16
+	`
+
+	_, err := tx.Exec(
+		query,
+		credit.Term,
+		credit.InterestRate,
+		credit.MonthlyPayment,
+		credit.TotalPayment,
+		credit.Status,
+		credit.Score,
+		credit.DecisionReason,
+		credit.RateAdjustment,
+		credit.DecidedAt,
+		credit.StartDate,
+		credit.EndDate,
+		credit.FullCostRate,
+		credit.FullCostAmount,
+		credit.KeyRate,
+		credit.KeyRateStale,
+		credit.ID,
+	)
+	return err
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+type DisputeRepository interface {
+	Create(dispute models.CardDispute) (int64, error)
+	GetByID(id int64) (models.CardDispute, error)
+	GetByUserID(userID int64) ([]models.CardDispute, error)
+	GetByStatus(status models.DisputeStatus) ([]models.CardDispute, error)
+	CheckTransactionDisputed(transactionID int64) (bool, error)
+	UpdateStatus(id int64, from, to models.DisputeStatus) (bool, error)
+	BeginTx() (*sql.Tx, error)
+	ResolveTx(tx *sql.Tx, dispute models.CardDispute) (bool, error)
+	FinalizeReversalTx(tx *sql.Tx, id int64, finalReversalAt time.Time) (bool, error)
+}
+
+type PostgresDisputeRepository struct {
+	db *sql.DB
+}
+
+func NewDisputeRepository(db *sql.DB) DisputeRepository {
+	return &PostgresDisputeRepository{db: db}
+}
+
+func (r *PostgresDisputeRepository) Create(dispute models.CardDispute) (int64, error) {
+	query := `
+		INSERT INTO card_disputes (user_id, transaction_id, account_id, amount, reason_code, description, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	var id int64
+	err := r.db.QueryRow(
+		query,
+		dispute.UserID,
+		dispute.TransactionID,
+		dispute.AccountID,
+		dispute.Amount,
+		dispute.ReasonCode,
+		dispute.Description,
+		dispute.Status,
+		dispute.CreatedAt,
+		dispute.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *PostgresDisputeRepository) GetByID(id int64) (models.CardDispute, error) {
+	query := `
+		SELECT id, user_id, transaction_id, account_id, amount, reason_code, description, status, resolution_comment,
+		       provisional_credit_tx_id, final_reversal_at, resolved_at, created_at, updated_at
+		FROM card_disputes
+		WHERE id = $1
+	`
+
+	var dispute models.CardDispute
+	var provisionalCreditTxID sql.NullInt64
+	var finalReversalAt, resolvedAt sql.NullTime
+
+	err := r.db.QueryRow(query, id).Scan(
+		&dispute.ID,
+		&dispute.UserID,
+		&dispute.TransactionID,
+		&dispute.AccountID,
+		&dispute.Amount,
+		&dispute.ReasonCode,
+		&dispute.Description,
+		&dispute.Status,
+		&dispute.ResolutionComment,
+		&provisionalCreditTxID,
+		&finalReversalAt,
+		&resolvedAt,
+		&dispute.CreatedAt,
+		&dispute.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.CardDispute{}, errors.New("dispute not found")
+		}
+		return models.CardDispute{}, err
+	}
+
+	if provisionalCreditTxID.Valid {
+		dispute.ProvisionalCreditTxID = &provisionalCreditTxID.Int64
+	}
+
+	if finalReversalAt.Valid {
+		dispute.FinalReversalAt = &finalReversalAt.Time
+	}
+
+	if resolvedAt.Valid {
+		dispute.ResolvedAt = &resolvedAt.Time
+	}
+
+	return dispute, nil
+}
+
+func (r *PostgresDisputeRepository) GetByUserID(userID int64) ([]models.CardDispute, error) {
+	query := `
+		SELECT id, user_id, transaction_id, account_id, amount, reason_code, description, status, resolution_comment,
+		       provisional_credit_tx_id, final_reversal_at, resolved_at, created_at, updated_at
+		FROM card_disputes
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.queryDisputes(query, userID)
+}
+
+func (r *PostgresDisputeRepository) GetByStatus(status models.DisputeStatus) ([]models.CardDispute, error) {
+	query := `
+		SELECT id, user_id, transaction_id, account_id, amount, reason_code, description, status, resolution_comment,
+		       provisional_credit_tx_id, final_reversal_at, resolved_at, created_at, updated_at
+		FROM card_disputes
+		WHERE status = $1
+		ORDER BY created_at
+	`
+
+	return r.queryDisputes(query, status)
+}
+
+func (r *PostgresDisputeRepository) queryDisputes(query string, args ...interface{}) ([]models.CardDispute, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disputes []models.CardDispute
+	for rows.Next() {
+		var dispute models.CardDispute
+		var provisionalCreditTxID sql.NullInt64
+		var finalReversalAt, resolvedAt sql.NullTime
+
+		if err := rows.Scan(
+			&dispute.ID,
+			&dispute.UserID,
+			&dispute.TransactionID,
+			&dispute.AccountID,
+			&dispute.Amount,
+			&dispute.ReasonCode,
+			&dispute.Description,
+			&dispute.Status,
+			&dispute.ResolutionComment,
+			&provisionalCreditTxID,
+			&finalReversalAt,
+			&resolvedAt,
+			&dispute.CreatedAt,
+			&dispute.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if provisionalCreditTxID.Valid {
+			dispute.ProvisionalCreditTxID = &provisionalCreditTxID.Int64
+		}
+
+		if finalReversalAt.Valid {
+			dispute.FinalReversalAt = &finalReversalAt.Time
+		}
+
+		if resolvedAt.Valid {
+			dispute.ResolvedAt = &resolvedAt.Time
+		}
+
+		disputes = append(disputes, dispute)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return disputes, nil
+}
+
+func (r *PostgresDisputeRepository) CheckTransactionDisputed(transactionID int64) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM card_disputes WHERE transaction_id = $1 AND status <> $2)`
+
+	var exists bool
+	err := r.db.QueryRow(query, transactionID, models.DisputeStatusRejected).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// UpdateStatus меняет статус спора, только если текущий статус равен from
+func (r *PostgresDisputeRepository) UpdateStatus(id int64, from, to models.DisputeStatus) (bool, error) {
+	query := `
+		UPDATE card_disputes
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+	`
+
+	result, err := r.db.Exec(query, to, id, from)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+func (r *PostgresDisputeRepository) BeginTx() (*sql.Tx, error) {
+	return r.db.Begin()
+}
+
+// ResolveTx сохраняет решение по спору, только если спор еще на рассмотрении. Возвращает
+// false, если решение уже вынесено параллельным запросом.
+func (r *PostgresDisputeRepository) ResolveTx(tx *sql.Tx, dispute models.CardDispute) (bool, error) {
+	query := `
+		UPDATE card_disputes
+		SET status = $1, resolution_comment = $2, provisional_credit_tx_id = $3,
+		    resolved_at = $4, updated_at = NOW()
+		WHERE id = $5 AND status = $6
+	`
+
+	result, err := tx.Exec(
+		query,
+		dispute.Status,
+		dispute.ResolutionComment,
+		dispute.ProvisionalCreditTxID,
+		dispute.ResolvedAt,
+		dispute.ID,
+		models.DisputeStatusUnderReview,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// FinalizeReversalTx отмечает окончательный возврат по принятому спору. Возвращает false,
+// если возврат уже проведен.
+func (r *PostgresDisputeRepository) FinalizeReversalTx(tx *sql.Tx, id int64, finalReversalAt time.Time) (bool, error) {
+	query := `
+		UPDATE card_disputes
+		SET final_reversal_at = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3 AND final_reversal_at IS NULL
+	`
+
+	result, err := tx.Exec(query, finalReversalAt, id, models.DisputeStatusAccepted)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
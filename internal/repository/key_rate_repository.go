@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+type KeyRateRepository interface {
+	GetCached() (*models.KeyRate, error)
+	SaveCached(rate models.KeyRate) error
+	SaveHistory(rates []models.KeyRate) error
+	GetHistory(from, to time.Time) ([]models.KeyRate, error)
+	GetOnDate(date time.Time) (*models.KeyRate, error)
+	GetLastHistoryDate() (*time.Time, error)
+}
+
+type PostgresKeyRateRepository struct {
+	db *sql.DB
+}
+
+func NewKeyRateRepository(db *sql.DB) KeyRateRepository {
+	return &PostgresKeyRateRepository{db: db}
+}
+
+// GetCached возвращает последнюю сохраненную ставку или nil, если ставка еще не получена
+func (r *PostgresKeyRateRepository) GetCached() (*models.KeyRate, error) {
+	query := `
+		SELECT rate, rate_date, fetched_at
+		FROM cbr_key_rate_cache
+		WHERE id = 1
+	`
+
+	var rate models.KeyRate
+	err := r.db.QueryRow(query).Scan(&rate.Rate, &rate.Date, &rate.FetchedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+func (r *PostgresKeyRateRepository) SaveCached(rate models.KeyRate) error {
+	query := `
+		INSERT INTO cbr_key_rate_cache (id, rate, rate_date, fetched_at)
+		VALUES (1, $1, $2, $3)
+		ON CONFLICT (id) DO UPDATE
+		SET rate = EXCLUDED.rate, rate_date = EXCLUDED.rate_date, fetched_at = EXCLUDED.fetched_at
+	`
+
+	_, err := r.db.Exec(query, rate.Rate, rate.Date, rate.FetchedAt)
+	return err
+}
+
+// SaveHistory сохраняет ставки истории. Ставка за уже загруженную дату перезаписывается.
+func (r *PostgresKeyRateRepository) SaveHistory(rates []models.KeyRate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO cbr_key_rates (rate_date, rate, fetched_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (rate_date) DO UPDATE
+		SET rate = EXCLUDED.rate, fetched_at = EXCLUDED.fetched_at
+	`
+
+	for _, rate := range rates {
+		if _, err := tx.Exec(query, rate.Date, rate.Rate, rate.FetchedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresKeyRateRepository) GetHistory(from, to time.Time) ([]models.KeyRate, error) {
+	query := `
+		SELECT rate, rate_date, fetched_at
+		FROM cbr_key_rates
+		WHERE rate_date BETWEEN $1 AND $2
+		ORDER BY rate_date
+	`
+
+	rows, err := r.db.Query(query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []models.KeyRate
+	for rows.Next() {
+		var rate models.KeyRate
+		if err := rows.Scan(&rate.Rate, &rate.Date, &rate.FetchedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// GetOnDate возвращает ставку, действовавшую на дату date, или nil, если история
+// на эту дату не загружена
+func (r *PostgresKeyRateRepository) GetOnDate(date time.Time) (*models.KeyRate, error) {
+	query := `
+		SELECT rate, rate_date, fetched_at
+		FROM cbr_key_rates
+		WHERE rate_date <= $1
+		ORDER BY rate_date DESC
+		LIMIT 1
+	`
+
+	var rate models.KeyRate
+	err := r.db.QueryRow(query, date).Scan(&rate.Rate, &rate.Date, &rate.FetchedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+func (r *PostgresKeyRateRepository) GetLastHistoryDate() (*time.Time, error) {
+	query := `SELECT MAX(rate_date) FROM cbr_key_rates`
+
+	var date sql.NullTime
+	if err := r.db.QueryRow(query).Scan(&date); err != nil {
+		return nil, err
+	}
+
+	if !date.Valid {
+		return nil, nil
+	}
+
+	return &date.Time, nil
+}
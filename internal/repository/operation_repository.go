@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+type OperationRepository interface {
+	Create(operation models.PendingOperation) (int64, error)
+	GetByID(id int64) (models.PendingOperation, error)
+	IncrementAttempts(id int64, maxAttempts int) (int, models.OperationStatus, error)
+	MarkConfirmed(id int64, confirmedAt time.Time) (bool, error)
+	UpdateStatus(id int64, from, to models.OperationStatus) (bool, error)
+}
+
+type PostgresOperationRepository struct {
+	db *sql.DB
+}
+
+func NewOperationRepository(db *sql.DB) OperationRepository {
+	return &PostgresOperationRepository{db: db}
+}
+
+func (r *PostgresOperationRepository) Create(operation models.PendingOperation) (int64, error) {
+	query := `
+		INSERT INTO pending_operations (user_id, type, amount, payload, code_hash, attempts, status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	var id int64
+	err := r.db.QueryRow(
+		query,
+		operation.UserID,
+		operation.Type,
+		operation.Amount,
+		[]byte(operation.Payload),
+		operation.CodeHash,
+		operation.Attempts,
+		operation.Status,
+		operation.ExpiresAt,
+		operation.CreatedAt,
+		operation.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *PostgresOperationRepository) GetByID(id int64) (models.PendingOperation, error) {
+	query := `
+		SELECT id, user_id, type, amount, payload, code_hash, attempts, status, expires_at, confirmed_at, created_at, updated_at
+		FROM pending_operations
+		WHERE id = $1
+	`
+
+	var operation models.PendingOperation
+	var payload []byte
+	var confirmedAt sql.NullTime
+
+	err := r.db.QueryRow(query, id).Scan(
+		&operation.ID,
+		&operation.UserID,
+		&operation.Type,
+		&operation.Amount,
+		&payload,
+		&operation.CodeHash,
+		&operation.Attempts,
+		&operation.Status,
+		&operation.ExpiresAt,
+		&confirmedAt,
+		&operation.CreatedAt,
+		&operation.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.PendingOperation{}, errors.New("operation not found")
+		}
+		return models.PendingOperation{}, err
+	}
+
+	operation.Payload = payload
+
+	if confirmedAt.Valid {
+		operation.ConfirmedAt = &confirmedAt.Time
+	}
+
+	return operation, nil
+}
+
+// IncrementAttempts увеличивает счетчик неверных попыток ожидающей операции и блокирует ее,
+// когда счетчик достигает maxAttempts. Возвращает новые значения счетчика и статуса;
+// если операция уже не ожидает подтверждения, возвращается sql.ErrNoRows.
+func (r *PostgresOperationRepository) IncrementAttempts(id int64, maxAttempts int) (int, models.OperationStatus, error) {
+	query := `
+		UPDATE pending_operations
+		SET attempts = attempts + 1,
+			status = CASE WHEN attempts + 1 >= $2 THEN $3 ELSE status END,
+			updated_at = NOW()
+		WHERE id = $1 AND status = $4
+		RETURNING attempts, status
+	`
+
+	var attempts int
+	var status models.OperationStatus
+	err := r.db.QueryRow(query, id, maxAttempts, models.OperationStatusLocked, models.OperationStatusPending).Scan(&attempts, &status)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return attempts, status, nil
+}
+
+// MarkConfirmed переводит операцию из PENDING в CONFIRMED. Возвращает false, если
+// операция уже не ожидает подтверждения (например, ее подтвердил параллельный запрос).
+func (r *PostgresOperationRepository) MarkConfirmed(id int64, confirmedAt time.Time) (bool, error) {
+	query := `
+		UPDATE pending_operations
+		SET status = $1, confirmed_at = $2, updated_at = NOW()
+		WHERE id = $3 AND status = $4
+	`
+
+	result, err := r.db.Exec(query, models.OperationStatusConfirmed, confirmedAt, id, models.OperationStatusPending)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// UpdateStatus меняет статус операции, только если текущий статус равен from
+func (r *PostgresOperationRepository) UpdateStatus(id int64, from, to models.OperationStatus) (bool, error) {
+	query := `
+		UPDATE pending_operations
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+	`
+
+	result, err := r.db.Exec(query, to, id, from)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
@@ -10,11 +10,15 @@ import (
 type PaymentRepository interface {
 	Create(payment models.PaymentSchedule) (int64, error)
 	GetByCreditID(creditID int64) ([]models.PaymentSchedule, error)
-	GetPendingPayments() ([]models.PaymentSchedule, error)
+	GetDuePayments(asOf time.Time) ([]models.PaymentSchedule, error)
+	GetOverduePayments() ([]models.PaymentSchedule, error)
 	UpdateStatus(id int64, status models.PaymentStatus, paidDate *time.Time) error
+	RecordPaymentTx(tx *sql.Tx, id int64, paidAmount float64, status models.PaymentStatus, paidDate *time.Time) error
 	CreateBatch(payments []models.PaymentSchedule) error
 	CreateTx(tx *sql.Tx, payment models.PaymentSchedule) (int64, error)
 	CreateBatchTx(tx *sql.Tx, payments []models.PaymentSchedule) error
+	CancelPendingTx(tx *sql.Tx, creditID int64) error
+	CancelPendingFromTx(tx *sql.Tx, creditID int64, from time.Time) error
 }
 
 type PostgresPaymentRepository struct {
@@ -55,7 +59,7 @@ func (r *PostgresPaymentRepository) Create(payment models.PaymentSchedule) (int6
 
 func (r *PostgresPaymentRepository) GetByCreditID(creditID int64) ([]models.PaymentSchedule, error) {
 	query := `
-		SELECT id, credit_id, payment_date, amount, principal, interest, remaining_debt, status, paid_date, created_at, updated_at
+		SELECT id, credit_id, payment_date, amount, principal, interest, remaining_debt, status, paid_amount, paid_date, created_at, updated_at
 		FROM payment_schedules
 		WHERE credit_id = $1
 		ORDER BY payment_date
@@ -81,6 +85,7 @@ func (r *PostgresPaymentRepository) GetByCreditID(creditID int64) ([]models.Paym
 			&payment.Interest,
 			&payment.RemainingDebt,
 			&payment.Status,
+			&payment.PaidAmount,
 			&paidDate,
 			&payment.CreatedAt,
 			&payment.UpdatedAt,
@@ -102,15 +107,67 @@ func (r *PostgresPaymentRepository) GetByCreditID(creditID int64) ([]models.Paym
 	return payments, nil
 }
 
-func (r *PostgresPaymentRepository) GetPendingPayments() ([]models.PaymentSchedule, error) {
+// GetDuePayments возвращает неоплаченные (PENDING и OVERDUE) платежи, срок которых наступил к asOf.
+// Платежи упорядочены по кредиту и дате, чтобы более ранние гасились первыми.
+func (r *PostgresPaymentRepository) GetDuePayments(asOf time.Time) ([]models.PaymentSchedule, error) {
 	query := `
-		SELECT id, credit_id, payment_date, amount, principal, interest, remaining_debt, status, paid_date, created_at, updated_at
+		SELECT id, credit_id, payment_date, amount, principal, interest, remaining_debt, status, paid_amount, paid_date, created_at, updated_at
 		FROM payment_schedules
-		WHERE status = $1 AND payment_date <= NOW()
+		WHERE status IN ($1, $2) AND payment_date <= $3
+		ORDER BY credit_id, payment_date
+	`
+
+	rows, err := r.db.Query(query, models.PaymentStatusPending, models.PaymentStatusOverdue, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []models.PaymentSchedule
+	for rows.Next() {
+		var payment models.PaymentSchedule
+		var paidDate sql.NullTime
+
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.CreditID,
+			&payment.PaymentDate,
+			&payment.Amount,
+			&payment.Principal,
+			&payment.Interest,
+			&payment.RemainingDebt,
+			&payment.Status,
+			&payment.PaidAmount,
+			&paidDate,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if paidDate.Valid {
+			payment.PaidDate = &paidDate.Time
+		}
+
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (r *PostgresPaymentRepository) GetOverduePayments() ([]models.PaymentSchedule, error) {
+	query := `
+		SELECT id, credit_id, payment_date, amount, principal, interest, remaining_debt, status, paid_amount, paid_date, created_at, updated_at
+		FROM payment_schedules
+		WHERE status = $1
 		ORDER BY payment_date
 	`
 
-	rows, err := r.db.Query(query, models.PaymentStatusPending)
+	rows, err := r.db.Query(query, models.PaymentStatusOverdue)
 	if err != nil {
 		return nil, err
 	}
@@ -130,6 +187,7 @@ func (r *PostgresPaymentRepository) GetPendingPayments() ([]models.PaymentSchedu
 			&payment.Interest,
 			&payment.RemainingDebt,
 			&payment.Status,
+			&payment.PaidAmount,
 			&paidDate,
 			&payment.CreatedAt,
 			&payment.UpdatedAt,
@@ -175,6 +233,18 @@ func (r *PostgresPaymentRepository) UpdateStatus(id int64, status models.Payment
 	return err
 }
 
+// RecordPaymentTx сохраняет внесенную по платежу сумму, его статус и дату оплаты
+func (r *PostgresPaymentRepository) RecordPaymentTx(tx *sql.Tx, id int64, paidAmount float64, status models.PaymentStatus, paidDate *time.Time) error {
+	query := `
+		UPDATE payment_schedules
+		SET paid_amount = $1, status = $2, paid_date = $3, updated_at = NOW()
+		WHERE id = $4
+	`
+
+	_, err := tx.Exec(query, paidAmount, status, paidDate, id)
+	return err
+}
+
 func (r *PostgresPaymentRepository) CreateBatch(payments []models.PaymentSchedule) error {
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -217,8 +287,8 @@ func (r *PostgresPaymentRepository) CreateBatch(payments []models.PaymentSchedul
 
 func (r *PostgresPaymentRepository) CreateTx(tx *sql.Tx, payment models.PaymentSchedule) (int64, error) {
 	query := `
-		INSERT INTO payment_schedules (credit_id, payment_date, amount, principal, interest, remaining_debt, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO payment_schedules (credit_id, payment_date, amount, principal, interest, remaining_debt, status, paid_amount, paid_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id
 	`
 
@@ -232,6 +302,8 @@ func (r *PostgresPaymentRepository) CreateTx(tx *sql.Tx, payment models.PaymentS
 		payment.Interest,
 		payment.RemainingDebt,
 		payment.Status,
+		payment.PaidAmount,
+		payment.PaidDate,
 		payment.CreatedAt,
 		payment.UpdatedAt,
 	).Scan(&id)
@@ -275,3 +347,26 @@ func (r *PostgresPaymentRepository) CreateBatchTx(tx *sql.Tx, payments []models.
 
 	return nil
 }
+
+// CancelPendingFromTx отменяет неоплаченные платежи кредита с датой не ранее from
+func (r *PostgresPaymentRepository) CancelPendingFromTx(tx *sql.Tx, creditID int64, from time.Time) error {
+	query := `
+		UPDATE payment_schedules
+		SET status = $1, updated_at = NOW()
+		WHERE credit_id = $2 AND status = $3 AND payment_date >= $4
+	`
+
+	_, err := tx.Exec(query, models.PaymentStatusCanceled, creditID, models.PaymentStatusPending, from)
+	return err
+}
+
+func (r *PostgresPaymentRepository) CancelPendingTx(tx *sql.Tx, creditID int64) error {
+	query := `
+		UPDATE payment_schedules
+		SET status = $1, updated_at = NOW()
+		WHERE credit_id = $2 AND status = $3
+	`
+
+	_, err := tx.Exec(query, models.PaymentStatusCanceled, creditID, models.PaymentStatusPending)
+	return err
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+type PenaltyRepository interface {
+	Create(penalty models.CreditPenalty) (int64, error)
+	GetByCreditID(creditID int64) ([]models.CreditPenalty, error)
+	GetUnpaidByCreditID(creditID int64) ([]models.CreditPenalty, error)
+	MarkPaidTx(tx *sql.Tx, id int64, paidDate time.Time) error
+}
+
+type PostgresPenaltyRepository struct {
+	db *sql.DB
+}
+
+func NewPenaltyRepository(db *sql.DB) PenaltyRepository {
+	return &PostgresPenaltyRepository{db: db}
+}
+
+func (r *PostgresPenaltyRepository) Create(penalty models.CreditPenalty) (int64, error) {
+	query := `
+		INSERT INTO credit_penalties (credit_id, payment_schedule_id, type, amount, accrual_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	var id int64
+	err := r.db.QueryRow(
+		query,
+		penalty.CreditID,
+		penalty.PaymentScheduleID,
+		penalty.Type,
+		penalty.Amount,
+		penalty.AccrualDate,
+		penalty.Status,
+		penalty.CreatedAt,
+		penalty.UpdatedAt,
+	).Scan(&id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *PostgresPenaltyRepository) GetByCreditID(creditID int64) ([]models.CreditPenalty, error) {
+	query := `
+		SELECT id, credit_id, payment_schedule_id, type, amount, accrual_date, status, paid_date, created_at, updated_at
+		FROM credit_penalties
+		WHERE credit_id = $1
+		ORDER BY accrual_date, id
+	`
+
+	return r.queryPenalties(query, creditID)
+}
+
+func (r *PostgresPenaltyRepository) GetUnpaidByCreditID(creditID int64) ([]models.CreditPenalty, error) {
+	query := `
+		SELECT id, credit_id, payment_schedule_id, type, amount, accrual_date, status, paid_date, created_at, updated_at
+		FROM credit_penalties
+		WHERE credit_id = $1 AND status = $2
+		ORDER BY accrual_date, id
+	`
+
+	return r.queryPenalties(query, creditID, models.PenaltyStatusUnpaid)
+}
+
+func (r *PostgresPenaltyRepository) queryPenalties(query string, args ...interface{}) ([]models.CreditPenalty, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var penalties []models.CreditPenalty
+	for rows.Next() {
+		var penalty models.CreditPenalty
+		var paidDate sql.NullTime
+
+		if err := rows.Scan(
+			&penalty.ID,
+			&penalty.CreditID,
+			&penalty.PaymentScheduleID,
+			&penalty.Type,
+			&penalty.Amount,
+			&penalty.AccrualDate,
+			&penalty.Status,
+			&paidDate,
+			&penalty.CreatedAt,
+			&penalty.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if paidDate.Valid {
+			penalty.PaidDate = &paidDate.Time
+		}
+
+		penalties = append(penalties, penalty)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return penalties, nil
+}
+
+func (r *PostgresPenaltyRepository) MarkPaidTx(tx *sql.Tx, id int64, paidDate time.Time) error {
+	query := `
+		UPDATE credit_penalties
+		SET status = $1, paid_date = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	_, err := tx.Exec(query, models.PenaltyStatusPaid, paidDate, id)
+	return err
+}
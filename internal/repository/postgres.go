@@ -32,6 +32,14 @@ type Repositories struct {
 	Transaction TransactionRepository
 	Credit      CreditRepository
 	Payment     PaymentRepository
+	Dispute     DisputeRepository
+	Operation   OperationRepository
+	CardHold    CardHoldRepository
+	Penalty     PenaltyRepository
+	Product     CreditProductRepository
+	CreditLine  CreditLineRepository
+	Collection  CollectionRepository
+	KeyRate     KeyRateRepository
 }
 
 func NewRepositories(db *sql.DB) *Repositories {
@@ -42,5 +50,13 @@ func NewRepositories(db *sql.DB) *Repositories {
 		Transaction: NewTransactionRepository(db),
 		Credit:      NewCreditRepository(db),
 		Payment:     NewPaymentRepository(db),
+		Dispute:     NewDisputeRepository(db),
+		Operation:   NewOperationRepository(db),
+		CardHold:    NewCardHoldRepository(db),
+		Penalty:     NewPenaltyRepository(db),
+		Product:     NewCreditProductRepository(db),
+		CreditLine:  NewCreditLineRepository(db),
+		Collection:  NewCollectionRepository(db),
+		KeyRate:     NewKeyRateRepository(db),
 	}
 }
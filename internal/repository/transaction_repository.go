@@ -13,7 +13,9 @@ type TransactionRepository interface {
 	GetByUserID(userID int64, limit, offset int) ([]models.Transaction, error)
 	GetByAccountID(accountID int64, limit, offset int) ([]models.Transaction, error)
 	GetUserTransactionsByPeriod(userID int64, startDate, endDate time.Time) ([]models.Transaction, error)
+	GetAccountTurnover(accountID int64, from, to time.Time) (float64, float64, error)
 	CreateTx(tx *sql.Tx, transaction models.Transaction) (int64, error)
+	UpdateStatusTx(tx *sql.Tx, id int64, status string) error
 }
 
 type PostgresTransactionRepository struct {
@@ -26,8 +28,8 @@ func NewTransactionRepository(db *sql.DB) TransactionRepository {
 
 func (r *PostgresTransactionRepository) Create(transaction models.Transaction) (int64, error) {
 	query := `
-		INSERT INTO transactions (user_id, from_account_id, to_account_id, type, amount, description, status, transaction_date, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO transactions (user_id, from_account_id, to_account_id, type, amount, description, status, credit_id, payment_schedule_id, card_id, transaction_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id
 	`
 
@@ -41,6 +43,9 @@ func (r *PostgresTransactionRepository) Create(transaction models.Transaction) (
 		transaction.Amount,
 		transaction.Description,
 		transaction.Status,
+		transaction.CreditID,
+		transaction.PaymentScheduleID,
+		transaction.CardID,
 		transaction.TransactionDate,
 		transaction.CreatedAt,
 	).Scan(&id)
@@ -54,13 +59,13 @@ func (r *PostgresTransactionRepository) Create(transaction models.Transaction) (
 
 func (r *PostgresTransactionRepository) GetByID(id int64) (models.Transaction, error) {
 	query := `
-		SELECT id, user_id, from_account_id, to_account_id, type, amount, description, status, transaction_date, created_at
+		SELECT id, user_id, from_account_id, to_account_id, type, amount, description, status, credit_id, payment_schedule_id, card_id, transaction_date, created_at
 		FROM transactions
 		WHERE id = $1
 	`
 
 	var transaction models.Transaction
-	var fromAccountID, toAccountID sql.NullInt64
+	var fromAccountID, toAccountID, creditID, paymentScheduleID, cardID sql.NullInt64
 
 	err := r.db.QueryRow(query, id).Scan(
 		&transaction.ID,
@@ -71,6 +76,9 @@ func (r *PostgresTransactionRepository) GetByID(id int64) (models.Transaction, e
 		&transaction.Amount,
 		&transaction.Description,
 		&transaction.Status,
+		&creditID,
+		&paymentScheduleID,
+		&cardID,
 		&transaction.TransactionDate,
 		&transaction.CreatedAt,
 	)
@@ -87,12 +95,24 @@ func (r *PostgresTransactionRepository) GetByID(id int64) (models.Transaction, e
 		transaction.ToAccountID = &toAccountID.Int64
 	}
 
+	if creditID.Valid {
+		transaction.CreditID = &creditID.Int64
+	}
+
+	if paymentScheduleID.Valid {
+		transaction.PaymentScheduleID = &paymentScheduleID.Int64
+	}
+
+	if cardID.Valid {
+		transaction.CardID = &cardID.Int64
+	}
+
 	return transaction, nil
 }
 
 func (r *PostgresTransactionRepository) GetByUserID(userID int64, limit, offset int) ([]models.Transaction, error) {
 	query := `
-		SELECT id, user_id, from_account_id, to_account_id, type, amount, description, status, transaction_date, created_at
+		SELECT id, user_id, from_account_id, to_account_id, type, amount, description, status, credit_id, payment_schedule_id, card_id, transaction_date, created_at
 		FROM transactions
 		WHERE user_id = $1
 		ORDER BY transaction_date DESC
@@ -108,7 +128,7 @@ func (r *PostgresTransactionRepository) GetByUserID(userID int64, limit, offset
 	var transactions []models.Transaction
 	for rows.Next() {
 		var transaction models.Transaction
-		var fromAccountID, toAccountID sql.NullInt64
+		var fromAccountID, toAccountID, creditID, paymentScheduleID, cardID sql.NullInt64
 
 		if err := rows.Scan(
 			&transaction.ID,
@@ -119,6 +139,9 @@ func (r *PostgresTransactionRepository) GetByUserID(userID int64, limit, offset
 			&transaction.Amount,
 			&transaction.Description,
 			&transaction.Status,
+			&creditID,
+			&paymentScheduleID,
+			&cardID,
 			&transaction.TransactionDate,
 			&transaction.CreatedAt,
 		); err != nil {
@@ -133,6 +156,18 @@ func (r *PostgresTransactionRepository) GetByUserID(userID int64, limit, offset
 			transaction.ToAccountID = &toAccountID.Int64
 		}
 
+		if creditID.Valid {
+			transaction.CreditID = &creditID.Int64
+		}
+
+		if paymentScheduleID.Valid {
+			transaction.PaymentScheduleID = &paymentScheduleID.Int64
+		}
+
+		if cardID.Valid {
+			transaction.CardID = &cardID.Int64
+		}
+
 		transactions = append(transactions, transaction)
 	}
 
@@ -145,7 +180,7 @@ func (r *PostgresTransactionRepository) GetByUserID(userID int64, limit, offset
 
 func (r *PostgresTransactionRepository) GetByAccountID(accountID int64, limit, offset int) ([]models.Transaction, error) {
 	query := `
-		SELECT id, user_id, from_account_id, to_account_id, type, amount, description, status, transaction_date, created_at
+		SELECT id, user_id, from_account_id, to_account_id, type, amount, description, status, credit_id, payment_schedule_id, card_id, transaction_date, created_at
 		FROM transactions
 		WHERE from_account_id = $1 OR to_account_id = $1
 		ORDER BY transaction_date DESC
@@ -161,7 +196,7 @@ func (r *PostgresTransactionRepository) GetByAccountID(accountID int64, limit, o
 	var transactions []models.Transaction
 	for rows.Next() {
 		var transaction models.Transaction
-		var fromAccountID, toAccountID sql.NullInt64
+		var fromAccountID, toAccountID, creditID, paymentScheduleID, cardID sql.NullInt64
 
 		if err := rows.Scan(
 			&transaction.ID,
@@ -172,6 +207,9 @@ func (r *PostgresTransactionRepository) GetByAccountID(accountID int64, limit, o
 			&transaction.Amount,
 			&transaction.Description,
 			&transaction.Status,
+			&creditID,
+			&paymentScheduleID,
+			&cardID,
 			&transaction.TransactionDate,
 			&transaction.CreatedAt,
 		); err != nil {
@@ -186,6 +224,18 @@ func (r *PostgresTransactionRepository) GetByAccountID(accountID int64, limit, o
 			transaction.ToAccountID = &toAccountID.Int64
 		}
 
+		if creditID.Valid {
+			transaction.CreditID = &creditID.Int64
+		}
+
+		if paymentScheduleID.Valid {
+			transaction.PaymentScheduleID = &paymentScheduleID.Int64
+		}
+
+		if cardID.Valid {
+			transaction.CardID = &cardID.Int64
+		}
+
 		transactions = append(transactions, transaction)
 	}
 
@@ -198,7 +248,7 @@ func (r *PostgresTransactionRepository) GetByAccountID(accountID int64, limit, o
 
 func (r *PostgresTransactionRepository) GetUserTransactionsByPeriod(userID int64, startDate, endDate time.Time) ([]models.Transaction, error) {
 	query := `
-		SELECT id, user_id, from_account_id, to_account_id, type, amount, description, status, transaction_date, created_at
+		SELECT id, user_id, from_account_id, to_account_id, type, amount, description, status, credit_id, payment_schedule_id, card_id, transaction_date, created_at
 		FROM transactions
 		WHERE user_id = $1 AND transaction_date BETWEEN $2 AND $3
 		ORDER BY transaction_date
@@ -213,7 +263,7 @@ func (r *PostgresTransactionRepository) GetUserTransactionsByPeriod(userID int64
 	var transactions []models.Transaction
 	for rows.Next() {
 		var transaction models.Transaction
-		var fromAccountID, toAccountID sql.NullInt64
+		var fromAccountID, toAccountID, creditID, paymentScheduleID, cardID sql.NullInt64
 
 		if err := rows.Scan(
 			&transaction.ID,
@@ -224,6 +274,9 @@ func (r *PostgresTransactionRepository) GetUserTransactionsByPeriod(userID int64
 			&transaction.Amount,
 			&transaction.Description,
 			&transaction.Status,
+			&creditID,
+			&paymentScheduleID,
+			&cardID,
 			&transaction.TransactionDate,
 			&transaction.CreatedAt,
 		); err != nil {
@@ -238,6 +291,18 @@ func (r *PostgresTransactionRepository) GetUserTransactionsByPeriod(userID int64
 			transaction.ToAccountID = &toAccountID.Int64
 		}
 
+		if creditID.Valid {
+			transaction.CreditID = &creditID.Int64
+		}
+
+		if paymentScheduleID.Valid {
+			transaction.PaymentScheduleID = &paymentScheduleID.Int64
+		}
+
+		if cardID.Valid {
+			transaction.CardID = &cardID.Int64
+		}
+
 		transactions = append(transactions, transaction)
 	}
 
@@ -248,10 +313,28 @@ func (r *PostgresTransactionRepository) GetUserTransactionsByPeriod(userID int64
 	return transactions, nil
 }
 
+// GetAccountTurnover возвращает сумму списаний и поступлений по счету за период (from, to].
+// Отмененные операции не учитываются.
+func (r *PostgresTransactionRepository) GetAccountTurnover(accountID int64, from, to time.Time) (float64, float64, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(amount) FILTER (WHERE from_account_id = $1), 0),
+			COALESCE(SUM(amount) FILTER (WHERE to_account_id = $1), 0)
+		FROM transactions
+		WHERE (from_account_id = $1 OR to_account_id = $1)
+			AND transaction_date > $2 AND transaction_date <= $3
+			AND status <> $4
+	`
+
+	var debits, credits float64
+	err := r.db.QueryRow(query, accountID, from, to, models.TransactionStatusReversed).Scan(&debits, &credits)
+	return debits, credits, err
+}
+
 func (r *PostgresTransactionRepository) CreateTx(tx *sql.Tx, transaction models.Transaction) (int64, error) {
 	query := `
-		INSERT INTO transactions (user_id, from_account_id, to_account_id, type, amount, description, status, transaction_date, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO transactions (user_id, from_account_id, to_account_id, type, amount, description, status, credit_id, payment_schedule_id, card_id, transaction_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id
 	`
 
@@ -265,6 +348,9 @@ func (r *PostgresTransactionRepository) CreateTx(tx *sql.Tx, transaction models.
 		transaction.Amount,
 		transaction.Description,
 		transaction.Status,
+		transaction.CreditID,
+		transaction.PaymentScheduleID,
+		transaction.CardID,
 		transaction.TransactionDate,
 		transaction.CreatedAt,
 	).Scan(&id)
@@ -275,3 +361,14 @@ func (r *PostgresTransactionRepository) CreateTx(tx *sql.Tx, transaction models.
 
 	return id, nil
 }
+
+func (r *PostgresTransactionRepository) UpdateStatusTx(tx *sql.Tx, id int64, status string) error {
+	query := `
+		UPDATE transactions
+		SET status = $1
+		WHERE id = $2
+	`
+
+	_, err := tx.Exec(query, status, id)
+	return err
+}
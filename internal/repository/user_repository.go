@@ -15,6 +15,7 @@ type UserRepository interface {
 	CheckEmailExists(email string) (bool, error)
 	CheckUsernameExists(username string) (bool, error)
 	Update(user models.User) error
+	UpdateDeclaredIncome(id int64, declaredIncome float64) error
 }
 
 type PostgresUserRepository struct {
@@ -27,8 +28,8 @@ func NewUserRepository(db *sql.DB) UserRepository {
 
 func (r *PostgresUserRepository) Create(user models.User) (int64, error) {
 	query := `
-		INSERT INTO users (username, email, password_hash, full_name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (username, email, password_hash, full_name, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 
@@ -39,6 +40,7 @@ func (r *PostgresUserRepository) Create(user models.User) (int64, error) {
 		user.Email,
 		user.PasswordHash,
 		user.FullName,
+		user.Role,
 		user.CreatedAt,
 		user.UpdatedAt,
 	).Scan(&id)
@@ -50,24 +52,41 @@ func (r *PostgresUserRepository) Create(user models.User) (int64, error) {
 	return id, nil
 }
 
-func (r *PostgresUserRepository) GetByID(id int64) (models.User, error) {
-	query := `
-		SELECT id, username, email, password_hash, full_name, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
+const userColumns = `id, username, email, password_hash, full_name, role, declared_income, income_declared_at, created_at, updated_at`
 
+func scanUser(row rowScanner) (models.User, error) {
 	var user models.User
-	err := r.db.QueryRow(query, id).Scan(
+	var incomeDeclaredAt sql.NullTime
+
+	err := row.Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
 		&user.FullName,
+		&user.Role,
+		&user.DeclaredIncome,
+		&incomeDeclaredAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
+	if incomeDeclaredAt.Valid {
+		user.IncomeDeclaredAt = &incomeDeclaredAt.Time
+	}
+
+	return user, err
+}
+
+func (r *PostgresUserRepository) GetByID(id int64) (models.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id = $1
+	`
+
+	user, err := scanUser(r.db.QueryRow(query, id))
+
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, errors.New("user not found")
@@ -80,21 +99,12 @@ func (r *PostgresUserRepository) GetByID(id int64) (models.User, error) {
 
 func (r *PostgresUserRepository) GetByEmail(email string) (models.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, full_name, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE email = $1
 	`
 
-	var user models.User
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.PasswordHash,
-		&user.FullName,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRow(query, email))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -108,21 +118,12 @@ func (r *PostgresUserRepository) GetByEmail(email string) (models.User, error) {
 
 func (r *PostgresUserRepository) GetByUsername(username string) (models.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, full_name, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE username = $1
 	`
 
-	var user models.User
-	err := r.db.QueryRow(query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.PasswordHash,
-		&user.FullName,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRow(query, username))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -177,3 +178,15 @@ func (r *PostgresUserRepository) Update(user models.User) error {
 
 	return err
 }
+
+// UpdateDeclaredIncome сохраняет ежемесячный доход, указанный пользователем в профиле
+func (r *PostgresUserRepository) UpdateDeclaredIncome(id int64, declaredIncome float64) error {
+	query := `
+		UPDATE users
+		SET declared_income = $1, income_declared_at = NOW(), updated_at = NOW()
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(query, declaredIncome, id)
+	return err
+}
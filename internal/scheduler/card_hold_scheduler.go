@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"bank-service/internal/service"
+)
+
+// CardHoldScheduler снимает просроченные холды по картам
+type CardHoldScheduler struct {
+	cardService service.CardService
+	logger      *logrus.Logger
+	stopCh      chan struct{}
+}
+
+func NewCardHoldScheduler(cardService service.CardService, logger *logrus.Logger) *CardHoldScheduler {
+	return &CardHoldScheduler{
+		cardService: cardService,
+		logger:      logger,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+func (s *CardHoldScheduler) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Card hold scheduler started")
+
+	s.releaseExpiredHolds()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.releaseExpiredHolds()
+		case <-s.stopCh:
+			s.logger.Info("Card hold scheduler stopped")
+			return
+		}
+	}
+}
+
+func (s *CardHoldScheduler) Stop() {
+	close(s.stopCh)
+}
+
+func (s *CardHoldScheduler) releaseExpiredHolds() {
+	count, err := s.cardService.ReleaseExpiredHolds()
+	if err != nil {
+		s.logger.Errorf("Error releasing expired card holds: %v", err)
+	}
+
+	if count > 0 {
+		s.logger.Infof("Expired card holds released: %d", count)
+	}
+}
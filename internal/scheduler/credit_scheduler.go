@@ -9,16 +9,18 @@ import (
 )
 
 type CreditScheduler struct {
-	creditService service.CreditService
-	logger        *logrus.Logger
-	stopCh        chan struct{}
+	creditService     service.CreditService
+	creditLineService service.CreditLineService
+	logger            *logrus.Logger
+	stopCh            chan struct{}
 }
 
-func NewCreditScheduler(creditService service.CreditService, logger *logrus.Logger) *CreditScheduler {
+func NewCreditScheduler(creditService service.CreditService, creditLineService service.CreditLineService, logger *logrus.Logger) *CreditScheduler {
 	return &CreditScheduler{
-		creditService: creditService,
-		logger:        logger,
-		stopCh:        make(chan struct{}),
+		creditService:     creditService,
+		creditLineService: creditLineService,
+		logger:            logger,
+		stopCh:            make(chan struct{}),
 	}
 }
 
@@ -29,11 +31,13 @@ func (s *CreditScheduler) Start(interval time.Duration) {
 	s.logger.Info("Credit scheduler started")
 
 	s.processPayments()
+	s.processCreditLines()
 
 	for {
 		select {
 		case <-ticker.C:
 			s.processPayments()
+			s.processCreditLines()
 		case <-s.stopCh:
 			s.logger.Info("Credit scheduler stopped")
 			return
@@ -53,4 +57,40 @@ func (s *CreditScheduler) processPayments() {
 	} else {
 		s.logger.Info("Pending payments processed successfully")
 	}
+
+	if err := s.creditService.ProcessCollections(); err != nil {
+		s.logger.Errorf("Error processing collections: %v", err)
+	}
+
+	if err := s.creditService.AccruePenaltyInterest(); err != nil {
+		s.logger.Errorf("Error accruing penalty interest: %v", err)
+	}
+
+	if err := s.creditService.AccrueDailyInterest(); err != nil {
+		s.logger.Errorf("Error accruing daily interest: %v", err)
+	}
+
+	if err := s.creditService.RepriceFloatingCredits(); err != nil {
+		s.logger.Errorf("Error repricing floating-rate credits: %v", err)
+	}
+
+	if err := s.creditService.BackfillFullCost(); err != nil {
+		s.logger.Errorf("Error backfilling full cost of credits: %v", err)
+	}
+}
+
+func (s *CreditScheduler) processCreditLines() {
+	s.logger.Info("Processing credit lines")
+
+	if err := s.creditLineService.AccrueInterest(); err != nil {
+		s.logger.Errorf("Error accruing credit line interest: %v", err)
+	}
+
+	if err := s.creditLineService.GenerateStatements(); err != nil {
+		s.logger.Errorf("Error generating credit line statements: %v", err)
+	}
+
+	if err := s.creditLineService.EnforceMinimumPayments(); err != nil {
+		s.logger.Errorf("Error enforcing credit line minimum payments: %v", err)
+	}
 }
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"bank-service/internal/service"
+)
+
+// KeyRateScheduler загружает историю ключевой ставки ЦБ РФ
+type KeyRateScheduler struct {
+	cbrService service.CBRService
+	logger     *logrus.Logger
+	stopCh     chan struct{}
+}
+
+func NewKeyRateScheduler(cbrService service.CBRService, logger *logrus.Logger) *KeyRateScheduler {
+	return &KeyRateScheduler{
+		cbrService: cbrService,
+		logger:     logger,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+func (s *KeyRateScheduler) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Key rate scheduler started")
+
+	s.refreshHistory()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.refreshHistory()
+		case <-s.stopCh:
+			s.logger.Info("Key rate scheduler stopped")
+			return
+		}
+	}
+}
+
+func (s *KeyRateScheduler) Stop() {
+	close(s.stopCh)
+}
+
+func (s *KeyRateScheduler) refreshHistory() {
+	count, err := s.cbrService.RefreshKeyRateHistory(context.Background())
+	if err != nil {
+		s.logger.Errorf("Error refreshing key rate history: %v", err)
+		return
+	}
+
+	s.logger.Infof("Key rate history refreshed: %d records", count)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -26,17 +27,24 @@ type AccountService interface {
 	Withdraw(request models.WithdrawRequest, userID int64) error
 	Transfer(request models.TransferRequest, userID int64) error
 	PredictBalance(accountID int64, userID int64, days int) ([]models.BalancePrediction, error)
+	ExecuteConfirmed(operation models.PendingOperation) error
 }
 
 type accountService struct {
 	accountRepo     repository.AccountRepository
 	transactionRepo repository.TransactionRepository
+	operations      OperationService
 }
 
-func NewAccountService(accountRepo repository.AccountRepository, transactionRepo repository.TransactionRepository) AccountService {
+func NewAccountService(
+	accountRepo repository.AccountRepository,
+	transactionRepo repository.TransactionRepository,
+	operations OperationService,
+) AccountService {
 	return &accountService{
 		accountRepo:     accountRepo,
 		transactionRepo: transactionRepo,
+		operations:      operations,
 	}
 }
 
@@ -134,6 +142,10 @@ func (s *accountService) Deposit(request models.DepositRequest, userID int64) er
 }
 
 func (s *accountService) Withdraw(request models.WithdrawRequest, userID int64) error {
+	return s.withdraw(request, userID, false)
+}
+
+func (s *accountService) withdraw(request models.WithdrawRequest, userID int64, confirmed bool) error {
 	if request.Amount <= 0 {
 		return ErrInvalidAmount
 	}
@@ -151,6 +163,10 @@ func (s *accountService) Withdraw(request models.WithdrawRequest, userID int64)
 		return err
 	}
 
+	if !confirmed && s.operations.RequiresConfirmation(request.Amount) {
+		return s.operations.Request(userID, models.OperationTypeWithdraw, request.Amount, request)
+	}
+
 	tx, err := s.accountRepo.BeginTx()
 	if err != nil {
 		return err
@@ -181,6 +197,10 @@ func (s *accountService) Withdraw(request models.WithdrawRequest, userID int64)
 }
 
 func (s *accountService) Transfer(request models.TransferRequest, userID int64) error {
+	return s.transfer(request, userID, false)
+}
+
+func (s *accountService) transfer(request models.TransferRequest, userID int64, confirmed bool) error {
 	if request.Amount <= 0 {
 		return ErrInvalidAmount
 	}
@@ -207,6 +227,10 @@ func (s *accountService) Transfer(request models.TransferRequest, userID int64)
 		return err
 	}
 
+	if !confirmed && s.operations.RequiresConfirmation(request.Amount) {
+		return s.operations.Request(userID, models.OperationTypeTransfer, request.Amount, request)
+	}
+
 	tx, err := s.accountRepo.BeginTx()
 	if err != nil {
 		return err
@@ -242,6 +266,26 @@ func (s *accountService) Transfer(request models.TransferRequest, userID int64)
 	return tx.Commit()
 }
 
+// ExecuteConfirmed повторно проверяет и выполняет операцию, подтвержденную одноразовым кодом
+func (s *accountService) ExecuteConfirmed(operation models.PendingOperation) error {
+	switch operation.Type {
+	case models.OperationTypeWithdraw:
+		var request models.WithdrawRequest
+		if err := json.Unmarshal(operation.Payload, &request); err != nil {
+			return err
+		}
+		return s.withdraw(request, operation.UserID, true)
+	case models.OperationTypeTransfer:
+		var request models.TransferRequest
+		if err := json.Unmarshal(operation.Payload, &request); err != nil {
+			return err
+		}
+		return s.transfer(request, operation.UserID, true)
+	}
+
+	return fmt.Errorf("unsupported operation type %s", operation.Type)
+}
+
 func (s *accountService) PredictBalance(accountID int64, userID int64, days int) ([]models.BalancePrediction, error) {
 	if days <= 0 || days > 365 {
 		days = 30 // Значение по умолчанию
@@ -1,12 +1,22 @@
 package service
 
 import (
+	"math"
 	"time"
 
 	"bank-service/internal/models"
 	"bank-service/internal/repository"
 )
 
+// Окно, за которое доход оценивается по поступлениям, и число месячных периодов в нем
+const (
+	incomeWindowMonths = 3
+	incomeWindowDays   = incomeWindowMonths * 30
+)
+
+// Заявленный доход считается актуальным в течение года после указания
+const declaredIncomeValidity = 365 * 24 * time.Hour
+
 type AnalyticsService interface {
 	GetTransactionAnalytics(userID int64, period string) (models.TransactionAnalytics, error)
 	GetCreditAnalytics(userID int64) (models.CreditAnalytics, error)
@@ -16,17 +26,20 @@ type analyticsService struct {
 	transactionRepo repository.TransactionRepository
 	creditRepo      repository.CreditRepository
 	paymentRepo     repository.PaymentRepository
+	userRepo        repository.UserRepository
 }
 
 func NewAnalyticsService(
 	transactionRepo repository.TransactionRepository,
 	creditRepo repository.CreditRepository,
 	paymentRepo repository.PaymentRepository,
+	userRepo repository.UserRepository,
 ) AnalyticsService {
 	return &analyticsService{
 		transactionRepo: transactionRepo,
 		creditRepo:      creditRepo,
 		paymentRepo:     paymentRepo,
+		userRepo:        userRepo,
 	}
 }
 
@@ -79,7 +92,9 @@ func (s *analyticsService) GetTransactionAnalytics(userID int64, period string)
 			totalExpense += tx.Amount
 			daily.Expense += tx.Amount
 
-			if tx.Description == "Withdrawal from account" {
+			if tx.CreditID != nil {
+				categoryBreakdown["Credit repayment"] += tx.Amount
+			} else if tx.Description == "Withdrawal from account" {
 				categoryBreakdown["Cash"] += tx.Amount
 			} else if tx.Description == "Card payment" {
 				categoryBreakdown["Shopping"] += tx.Amount
@@ -87,6 +102,11 @@ func (s *analyticsService) GetTransactionAnalytics(userID int64, period string)
 				categoryBreakdown["Other"] += tx.Amount
 			}
 
+		case models.TransactionTypeCredit:
+			totalIncome += tx.Amount
+			daily.Income += tx.Amount
+			categoryBreakdown["Credit"] += tx.Amount
+
 		case models.TransactionTypeTransfer:
 			if tx.FromAccountID != nil {
 				totalExpense += tx.Amount
@@ -147,10 +167,14 @@ func (s *analyticsService) GetCreditAnalytics(userID int64) (models.CreditAnalyt
 		}
 	}
 
+	income, err := s.estimateIncome(userID, time.Now())
+	if err != nil {
+		return models.CreditAnalytics{}, err
+	}
+
 	debtToIncomeRatio := 0.0
-	if monthlyPayments > 0 {
-		estimatedMonthlyIncome := 100000.0
-		debtToIncomeRatio = monthlyPayments / estimatedMonthlyIncome
+	if monthlyPayments > 0 && income.MonthlyIncome > 0 {
+		debtToIncomeRatio = monthlyPayments / income.MonthlyIncome
 	}
 
 	return models.CreditAnalytics{
@@ -158,5 +182,88 @@ func (s *analyticsService) GetCreditAnalytics(userID int64) (models.CreditAnalyt
 		MonthlyPayments:   monthlyPayments,
 		DebtToIncomeRatio: debtToIncomeRatio,
 		RemainingCredits:  activeCredits,
+		Income:            income,
 	}, nil
 }
+
+// estimateIncome оценивает ежемесячный доход по пополнениям счетов за последние
+// incomeWindowMonths месяцев или по доходу, указанному в профиле. Регулярные поступления
+// каждый месяц окна считаются самым надежным источником; иначе используется заявленный
+// доход, а при его отсутствии - нерегулярные поступления с пониженной достоверностью.
+func (s *analyticsService) estimateIncome(userID int64, now time.Time) (models.IncomeEstimate, error) {
+	estimate := models.IncomeEstimate{
+		Method:     models.IncomeMethodNone,
+		Confidence: models.IncomeConfidenceNone,
+		WindowDays: incomeWindowDays,
+	}
+
+	transactions, err := s.transactionRepo.GetUserTransactionsByPeriod(userID, now.AddDate(0, 0, -incomeWindowDays), now)
+	if err != nil {
+		return models.IncomeEstimate{}, err
+	}
+
+	// Доходом считаются пополнения; выдача кредитов, переводы между своими счетами
+	// и возвраты по спорам не учитываются
+	var monthly [incomeWindowMonths]float64
+	var total float64
+	for _, transaction := range transactions {
+		if transaction.Type != models.TransactionTypeDeposit || transaction.Status == models.TransactionStatusReversed {
+			continue
+		}
+
+		month := int(now.Sub(transaction.TransactionDate).Hours() / 24 / 30)
+		if month >= incomeWindowMonths {
+			month = incomeWindowMonths - 1
+		}
+		monthly[month] += transaction.Amount
+		total += transaction.Amount
+	}
+
+	minMonthly, maxMonthly := math.Inf(1), 0.0
+	for _, amount := range monthly {
+		if amount > 0 {
+			estimate.MonthsWithIncome++
+		}
+		minMonthly = math.Min(minMonthly, amount)
+		maxMonthly = math.Max(maxMonthly, amount)
+	}
+	estimate.ObservedIncome = math.Round(total/incomeWindowMonths*100) / 100
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return models.IncomeEstimate{}, err
+	}
+	estimate.DeclaredIncome = user.DeclaredIncome
+
+	regular := estimate.MonthsWithIncome == incomeWindowMonths
+	switch {
+	case regular:
+		estimate.Method = models.IncomeMethodTransactions
+		estimate.MonthlyIncome = estimate.ObservedIncome
+		// Поступления каждый месяц без резких колебаний - высокая достоверность
+		estimate.Confidence = models.IncomeConfidenceMedium
+		if minMonthly >= maxMonthly/2 {
+			estimate.Confidence = models.IncomeConfidenceHigh
+		}
+	case user.DeclaredIncome > 0:
+		estimate.Method = models.IncomeMethodDeclared
+		estimate.MonthlyIncome = user.DeclaredIncome
+		estimate.Confidence = models.IncomeConfidenceLow
+		if user.IncomeDeclaredAt != nil && now.Sub(*user.IncomeDeclaredAt) <= declaredIncomeValidity {
+			estimate.Confidence = models.IncomeConfidenceMedium
+		}
+		// Заявленный доход, подтвержденный поступлениями, считается достоверным
+		if estimate.ObservedIncome >= user.DeclaredIncome/2 {
+			estimate.Confidence = models.IncomeConfidenceHigh
+		}
+	case estimate.MonthsWithIncome > 0:
+		estimate.Method = models.IncomeMethodTransactions
+		estimate.MonthlyIncome = estimate.ObservedIncome
+		estimate.Confidence = models.IncomeConfidenceLow
+		if estimate.MonthsWithIncome > 1 {
+			estimate.Confidence = models.IncomeConfidenceMedium
+		}
+	}
+
+	return estimate, nil
+}
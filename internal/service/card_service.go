@@ -1,11 +1,13 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
 	"time"
 
+	"bank-service/internal/config"
 	"bank-service/internal/models"
 	"bank-service/internal/repository"
 )
@@ -14,6 +16,12 @@ var (
 	ErrCardNotFound     = errors.New("card not found")
 	ErrCardAccessDenied = errors.New("access to this card is denied")
 	ErrCardInactive     = errors.New("card is inactive")
+	ErrCardBlocked      = errors.New("card is blocked until the overdue credit debt is repaid")
+
+	ErrCardExpired          = errors.New("card is expired")
+	ErrCardExpiryMismatch   = errors.New("card expiry date does not match")
+	ErrHoldNotFound         = errors.New("original authorization not found")
+	ErrDuplicateTransaction = errors.New("transaction with this RRN has already been processed")
 )
 
 type CardService interface {
@@ -22,19 +30,46 @@ type CardService interface {
 	GetByUserID(userID int64) ([]models.CardResponse, error)
 	UpdateStatus(id int64, isActive bool, userID int64) error
 	ProcessPayment(request models.CardPaymentRequest, userID int64) error
+	ExecuteConfirmed(operation models.PendingOperation) error
+
+	// Операции от терминалов (ISO 8583): держатель карты подтверждает их на POS,
+	// поэтому JWT и одноразовый код не требуются
+	AuthorizeHold(request models.TerminalRequest) (models.CardHold, error)
+	CompleteTerminalPayment(request models.TerminalRequest) (models.CardHold, error)
+	ReverseTerminalOperation(request models.TerminalRequest) (models.CardHold, error)
+
+	// ReleaseExpiredHolds снимает холды, которые терминал не закрыл и не отменил
+	// за HoldTTL, и возвращает число снятых холдов
+	ReleaseExpiredHolds() (int, error)
 }
 
 type cardService struct {
-	cardRepo    repository.CardRepository
-	accountRepo repository.AccountRepository
-	encryption  EncryptionService
+	cardRepo        repository.CardRepository
+	accountRepo     repository.AccountRepository
+	transactionRepo repository.TransactionRepository
+	holdRepo        repository.CardHoldRepository
+	encryption      EncryptionService
+	operations      OperationService
+	config          config.ISO8583Config
 }
 
-func NewCardService(cardRepo repository.CardRepository, accountRepo repository.AccountRepository, encryption EncryptionService) CardService {
+func NewCardService(
+	cardRepo repository.CardRepository,
+	accountRepo repository.AccountRepository,
+	transactionRepo repository.TransactionRepository,
+	holdRepo repository.CardHoldRepository,
+	encryption EncryptionService,
+	operations OperationService,
+	config config.ISO8583Config,
+) CardService {
 	return &cardService{
-		cardRepo:    cardRepo,
-		accountRepo: accountRepo,
-		encryption:  encryption,
+		cardRepo:        cardRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		holdRepo:        holdRepo,
+		encryption:      encryption,
+		operations:      operations,
+		config:          config,
 	}
 }
 
@@ -137,13 +172,14 @@ func (s *cardService) GetByID(id int64, userID int64) (models.CardResponse, erro
 	}
 
 	return models.CardResponse{
-		ID:         card.ID,
-		AccountID:  card.AccountID,
-		Number:     decryptedNumber,
-		ExpiryDate: decryptedExpiry,
-		Type:       card.Type,
-		IsActive:   card.IsActive,
-		CreatedAt:  card.CreatedAt,
+		ID:                   card.ID,
+		AccountID:            card.AccountID,
+		Number:               decryptedNumber,
+		ExpiryDate:           decryptedExpiry,
+		Type:                 card.Type,
+		IsActive:             card.IsActive,
+		BlockedByCollections: card.BlockedByCollections,
+		CreatedAt:            card.CreatedAt,
 	}, nil
 }
 
@@ -176,13 +212,14 @@ func (s *cardService) GetByUserID(userID int64) ([]models.CardResponse, error) {
 		maskedNumber := models.MaskCardNumber(decryptedNumber)
 
 		response = append(response, models.CardResponse{
-			ID:         card.ID,
-			AccountID:  card.AccountID,
-			Number:     maskedNumber,
-			ExpiryDate: decryptedExpiry,
-			Type:       card.Type,
-			IsActive:   card.IsActive,
-			CreatedAt:  card.CreatedAt,
+			ID:                   card.ID,
+			AccountID:            card.AccountID,
+			Number:               maskedNumber,
+			ExpiryDate:           decryptedExpiry,
+			Type:                 card.Type,
+			IsActive:             card.IsActive,
+			BlockedByCollections: card.BlockedByCollections,
+			CreatedAt:            card.CreatedAt,
 		})
 	}
 
@@ -203,6 +240,20 @@ func (s *cardService) UpdateStatus(id int64, isActive bool, userID int64) error
 }
 
 func (s *cardService) ProcessPayment(request models.CardPaymentRequest, userID int64) error {
+	return s.processPayment(request, userID, false)
+}
+
+// ExecuteConfirmed проводит оплату картой, подтвержденную одноразовым кодом
+func (s *cardService) ExecuteConfirmed(operation models.PendingOperation) error {
+	var request models.CardPaymentRequest
+	if err := json.Unmarshal(operation.Payload, &request); err != nil {
+		return err
+	}
+
+	return s.processPayment(request, operation.UserID, true)
+}
+
+func (s *cardService) processPayment(request models.CardPaymentRequest, userID int64, confirmed bool) error {
 	card, err := s.cardRepo.GetByID(request.CardID)
 	if err != nil {
 		return ErrCardNotFound
@@ -216,6 +267,10 @@ func (s *cardService) ProcessPayment(request models.CardPaymentRequest, userID i
 		return ErrCardInactive
 	}
 
+	if card.BlockedByCollections {
+		return ErrCardBlocked
+	}
+
 	account, err := s.accountRepo.GetByID(card.AccountID)
 	if err != nil {
 		return ErrAccountNotFound
@@ -225,6 +280,10 @@ func (s *cardService) ProcessPayment(request models.CardPaymentRequest, userID i
 		return err
 	}
 
+	if !confirmed && s.operations.RequiresConfirmation(request.Amount) {
+		return s.operations.Request(userID, models.OperationTypeCardPayment, request.Amount, request)
+	}
+
 	tx, err := s.accountRepo.BeginTx()
 	if err != nil {
 		return err
@@ -236,9 +295,338 @@ func (s *cardService) ProcessPayment(request models.CardPaymentRequest, userID i
 		return err
 	}
 
+	transaction := models.Transaction{
+		UserID:          userID,
+		FromAccountID:   &account.ID,
+		Type:            models.TransactionTypePayment,
+		Amount:          request.Amount,
+		Description:     "Card payment",
+		Status:          "COMPLETED",
+		CardID:          &card.ID,
+		TransactionDate: time.Now(),
+		CreatedAt:       time.Now(),
+	}
+
+	if _, err := s.transactionRepo.CreateTx(tx, transaction); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
+func (s *cardService) AuthorizeHold(request models.TerminalRequest) (models.CardHold, error) {
+	if request.Amount <= 0 {
+		return models.CardHold{}, ErrInvalidAmount
+	}
+
+	card, err := s.resolveTerminalCard(request)
+	if err != nil {
+		return models.CardHold{}, err
+	}
+
+	if _, err := s.holdRepo.GetByRRN(card.ID, request.RRN); err == nil {
+		return models.CardHold{}, ErrDuplicateTransaction
+	}
+
+	account, err := s.accountRepo.GetByID(card.AccountID)
+	if err != nil {
+		return models.CardHold{}, ErrAccountNotFound
+	}
+
+	if err := account.CanWithdraw(request.Amount); err != nil {
+		return models.CardHold{}, err
+	}
+
+	tx, err := s.holdRepo.BeginTx()
+	if err != nil {
+		return models.CardHold{}, err
+	}
+	defer tx.Rollback()
+
+	newBalance := account.Balance - request.Amount
+	if err := s.accountRepo.UpdateBalanceTx(tx, account.ID, newBalance); err != nil {
+		return models.CardHold{}, err
+	}
+
+	hold := newCardHold(card, request, models.CardHoldStatusActive)
+
+	hold.ID, err = s.holdRepo.CreateTx(tx, hold)
+	if err != nil {
+		return models.CardHold{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.CardHold{}, err
+	}
+
+	return hold, nil
+}
+
+// CompleteTerminalPayment списывает средства по финансовому сообщению. Если по RRN
+// уже есть активный холд, он закрывается, иначе оплата проводится без предавторизации.
+func (s *cardService) CompleteTerminalPayment(request models.TerminalRequest) (models.CardHold, error) {
+	if request.Amount <= 0 {
+		return models.CardHold{}, ErrInvalidAmount
+	}
+
+	card, err := s.resolveTerminalCard(request)
+	if err != nil {
+		return models.CardHold{}, err
+	}
+
+	account, err := s.accountRepo.GetByID(card.AccountID)
+	if err != nil {
+		return models.CardHold{}, ErrAccountNotFound
+	}
+
+	hold, err := s.holdRepo.GetByRRN(card.ID, request.RRN)
+	captureHold := err == nil
+
+	if captureHold {
+		if hold.Status != models.CardHoldStatusActive {
+			return models.CardHold{}, ErrDuplicateTransaction
+		}
+
+		if request.Amount > hold.Amount {
+			return models.CardHold{}, ErrInvalidAmount
+		}
+	} else {
+		if err := account.CanWithdraw(request.Amount); err != nil {
+			return models.CardHold{}, err
+		}
+	}
+
+	tx, err := s.holdRepo.BeginTx()
+	if err != nil {
+		return models.CardHold{}, err
+	}
+	defer tx.Rollback()
+
+	// Холд уже уменьшил баланс: возвращаем только разницу с итоговой суммой
+	change := -request.Amount
+	if captureHold {
+		change = hold.Amount - request.Amount
+	}
+
+	if err := s.accountRepo.AddToBalanceTx(tx, account.ID, change); err != nil {
+		return models.CardHold{}, err
+	}
+
+	now := time.Now()
+	transaction := models.Transaction{
+		UserID:          card.UserID,
+		FromAccountID:   &account.ID,
+		Type:            models.TransactionTypePayment,
+		Amount:          request.Amount,
+		Description:     "Card payment",
+		Status:          models.TransactionStatusCompleted,
+		CardID:          &card.ID,
+		TransactionDate: now,
+		CreatedAt:       now,
+	}
+
+	transactionID, err := s.transactionRepo.CreateTx(tx, transaction)
+	if err != nil {
+		return models.CardHold{}, err
+	}
+
+	if captureHold {
+		// Холд мог закрыть или отменить параллельный запрос с тем же RRN
+		captured, err := s.holdRepo.CaptureTx(tx, hold.ID, request.Amount, transactionID)
+		if err != nil {
+			return models.CardHold{}, err
+		}
+		if !captured {
+			return models.CardHold{}, ErrDuplicateTransaction
+		}
+		hold.Amount = request.Amount
+		hold.Status = models.CardHoldStatusCaptured
+		hold.TransactionID = &transactionID
+	} else {
+		hold = newCardHold(card, request, models.CardHoldStatusCaptured)
+		hold.TransactionID = &transactionID
+
+		hold.ID, err = s.holdRepo.CreateTx(tx, hold)
+		if err != nil {
+			return models.CardHold{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.CardHold{}, err
+	}
+
+	return hold, nil
+}
+
+// ReverseTerminalOperation отменяет холд или проведенную оплату по RRN.
+// Повторная отмена не считается ошибкой, так как терминалы повторяют реверсалы.
+func (s *cardService) ReverseTerminalOperation(request models.TerminalRequest) (models.CardHold, error) {
+	card, err := s.resolveTerminalCard(request)
+	if err != nil {
+		return models.CardHold{}, err
+	}
+
+	hold, err := s.holdRepo.GetByRRN(card.ID, request.RRN)
+	if err != nil {
+		return models.CardHold{}, ErrHoldNotFound
+	}
+
+	if hold.Status == models.CardHoldStatusReleased || hold.Status == models.CardHoldStatusReversed {
+		return hold, nil
+	}
+
+	tx, err := s.holdRepo.BeginTx()
+	if err != nil {
+		return models.CardHold{}, err
+	}
+	defer tx.Rollback()
+
+	status := models.CardHoldStatusReleased
+	if hold.Status == models.CardHoldStatusCaptured {
+		status = models.CardHoldStatusReversed
+	}
+
+	updated, err := s.holdRepo.UpdateStatusTx(tx, hold.ID, hold.Status, status)
+	if err != nil {
+		return models.CardHold{}, err
+	}
+	if !updated {
+		// Холд успел закрыть или отменить параллельный запрос: отменяем его в новом состоянии.
+		// Статус холда меняется только вперед, поэтому повторов не больше двух.
+		tx.Rollback()
+		return s.ReverseTerminalOperation(request)
+	}
+
+	// Для закрытого холда hold.Amount - фактически списанная сумма
+	if err := s.accountRepo.AddToBalanceTx(tx, hold.AccountID, hold.Amount); err != nil {
+		return models.CardHold{}, err
+	}
+
+	if status == models.CardHoldStatusReversed && hold.TransactionID != nil {
+		if err := s.transactionRepo.UpdateStatusTx(tx, *hold.TransactionID, models.TransactionStatusReversed); err != nil {
+			return models.CardHold{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.CardHold{}, err
+	}
+
+	hold.Status = status
+
+	return hold, nil
+}
+
+func (s *cardService) ReleaseExpiredHolds() (int, error) {
+	if s.config.HoldTTL <= 0 {
+		return 0, nil
+	}
+
+	holds, err := s.holdRepo.GetActiveCreatedBefore(time.Now().Add(-s.config.HoldTTL))
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, hold := range holds {
+		ok, err := s.releaseExpiredHold(hold)
+		if err != nil {
+			return released, fmt.Errorf("hold %d: %w", hold.ID, err)
+		}
+		if ok {
+			released++
+		}
+	}
+
+	return released, nil
+}
+
+func (s *cardService) releaseExpiredHold(hold models.CardHold) (bool, error) {
+	tx, err := s.holdRepo.BeginTx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Терминал мог закрыть или отменить холд после выборки
+	released, err := s.holdRepo.UpdateStatusTx(tx, hold.ID, models.CardHoldStatusActive, models.CardHoldStatusReleased)
+	if err != nil || !released {
+		return false, err
+	}
+
+	if err := s.accountRepo.AddToBalanceTx(tx, hold.AccountID, hold.Amount); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *cardService) resolveTerminalCard(request models.TerminalRequest) (models.Card, error) {
+	numberHMAC, err := s.encryption.CreateHMAC(request.PAN)
+	if err != nil {
+		return models.Card{}, err
+	}
+
+	card, err := s.cardRepo.GetByNumberHMAC(numberHMAC)
+	if err != nil {
+		return models.Card{}, ErrCardNotFound
+	}
+
+	if !card.IsActive {
+		return models.Card{}, ErrCardInactive
+	}
+
+	if card.BlockedByCollections {
+		return models.Card{}, ErrCardBlocked
+	}
+
+	expiry, err := s.encryption.DecryptData(card.ExpiryDate)
+	if err != nil {
+		return models.Card{}, err
+	}
+
+	if err := s.encryption.VerifyHMAC(expiry, card.ExpiryHMAC); err != nil {
+		return models.Card{}, errors.New("card expiry integrity check failed")
+	}
+
+	if request.Expiry != "" && request.Expiry != expiry {
+		return models.Card{}, ErrCardExpiryMismatch
+	}
+
+	expiresAt, err := time.Parse("01/06", expiry)
+	if err != nil {
+		return models.Card{}, err
+	}
+
+	// Карта действует до конца месяца, указанного на ней
+	if time.Now().After(expiresAt.AddDate(0, 1, 0)) {
+		return models.Card{}, ErrCardExpired
+	}
+
+	return card, nil
+}
+
+func newCardHold(card models.Card, request models.TerminalRequest, status models.CardHoldStatus) models.CardHold {
+	now := time.Now()
+	return models.CardHold{
+		CardID:       card.ID,
+		AccountID:    card.AccountID,
+		Amount:       request.Amount,
+		RRN:          request.RRN,
+		AuthCode:     generateAuthCode(),
+		TerminalID:   request.TerminalID,
+		MerchantName: request.MerchantName,
+		Status:       status,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
 func generateCardNumber() string {
 	rand.Seed(time.Now().UnixNano())
 
@@ -278,6 +666,12 @@ func generateExpiryDate() string {
 	return fmt.Sprintf("%02d/%02d", month, year%100)
 }
 
+func generateAuthCode() string {
+	rand.Seed(time.Now().UnixNano())
+
+	return fmt.Sprintf("%06d", rand.Intn(1000000))
+}
+
 func generateCVV() string {
 	rand.Seed(time.Now().UnixNano())
 
@@ -2,76 +2,305 @@ package service
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/beevik/etree"
+
+	"bank-service/internal/config"
+	"bank-service/internal/models"
+	"bank-service/internal/repository"
+)
+
+var (
+	ErrKeyRateUnavailable = errors.New("key rate is unavailable")
+	ErrInvalidDateRange   = errors.New("from date must not be after to date")
 )
 
+// Ключевая ставка установлена Банком России 13 сентября 2013 года
+var keyRateHistoryStart = time.Date(2013, time.September, 13, 0, 0, 0, 0, time.Local)
+
+// Период, за который запрашивается текущая ставка
+const keyRateLookbackDays = 30
+
+// Формат дат в параметрах методов веб-сервиса DailyInfo
+const soapDateLayout = "2006-01-02T15:04:05"
+
+// CBRService получает ключевую ставку ЦБ РФ. Ставка кэшируется в памяти и в базе данных
+// на CacheTTL. Если ЦБ РФ не ответил, возвращается последняя сохраненная ставка
+// с признаком Stale, а если ее нет - ErrKeyRateUnavailable. История ставки хранится
+// в базе данных и используется для получения ставки на прошедшую дату.
 type CBRService interface {
-	GetKeyRate() (float64, error)
+	GetKeyRate(ctx context.Context) (models.KeyRate, error)
+	GetKeyRateOnDate(ctx context.Context, date time.Time) (models.KeyRate, error)
+	GetKeyRateHistory(from, to time.Time) ([]models.KeyRate, error)
+	RefreshKeyRateHistory(ctx context.Context) (int, error)
 }
 
 type cbrService struct {
-	soapURL string
+	keyRateRepo repository.KeyRateRepository
+	client      *http.Client
+	config      config.CBRConfig
+
+	mu       sync.Mutex
+	cached   *models.KeyRate
+	failedAt time.Time
 }
 
-func NewCBRService() CBRService {
+func NewCBRService(keyRateRepo repository.KeyRateRepository, cfg config.CBRConfig) CBRService {
 	return &cbrService{
-		soapURL: "https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx",
+		keyRateRepo: keyRateRepo,
+		client:      &http.Client{},
+		config:      cfg,
+	}
+}
+
+func (s *cbrService) GetKeyRate(ctx context.Context) (models.KeyRate, error) {
+	// Одновременные запросы ждут одного обращения к ЦБ РФ, а не отправляют свои
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	if s.fresh(s.cached, now) {
+		return *s.cached, nil
+	}
+
+	// Ставку мог обновить другой экземпляр сервиса
+	if cached, err := s.keyRateRepo.GetCached(); err == nil && cached != nil {
+		if s.cached == nil || cached.FetchedAt.After(s.cached.FetchedAt) {
+			s.cached = cached
+		}
+		if s.fresh(s.cached, now) {
+			return *s.cached, nil
+		}
+	}
+
+	var err error
+	if now.Sub(s.failedAt) < s.config.FailureCooldown {
+		err = errors.New("previous request failed recently")
+	} else {
+		var rates []models.KeyRate
+		if rates, err = s.fetchWithRetry(ctx, now.AddDate(0, 0, -keyRateLookbackDays), now); err == nil {
+			rate := rates[len(rates)-1]
+			s.cached = &rate
+			s.failedAt = time.Time{}
+			s.keyRateRepo.SaveCached(rate)
+			return rate, nil
+		}
+		s.failedAt = now
+	}
+
+	if s.cached == nil {
+		return models.KeyRate{}, fmt.Errorf("%w: %v", ErrKeyRateUnavailable, err)
+	}
+
+	stale := *s.cached
+	stale.Stale = true
+	return stale, nil
+}
+
+func (s *cbrService) fresh(rate *models.KeyRate, now time.Time) bool {
+	return rate != nil && now.Sub(rate.FetchedAt) < s.config.CacheTTL
+}
+
+// GetKeyRateOnDate возвращает ставку, действовавшую на дату date. На текущий день ставка
+// определяется так же, как в GetKeyRate, на прошедшие даты - по сохраненной истории.
+func (s *cbrService) GetKeyRateOnDate(ctx context.Context, date time.Time) (models.KeyRate, error) {
+	if !startOfDay(date).Before(startOfDay(time.Now())) {
+		return s.GetKeyRate(ctx)
+	}
+
+	rate, err := s.keyRateRepo.GetOnDate(date)
+	if err != nil {
+		return models.KeyRate{}, err
 	}
+
+	if rate == nil {
+		return models.KeyRate{}, ErrKeyRateUnavailable
+	}
+
+	return *rate, nil
 }
 
-func (s *cbrService) GetKeyRate() (float64, error) {
-	soapRequest := `<?xml version="1.0" encoding="utf-8"?>
+func (s *cbrService) GetKeyRateHistory(from, to time.Time) ([]models.KeyRate, error) {
+	if from.After(to) {
+		return nil, ErrInvalidDateRange
+	}
+
+	rates, err := s.keyRateRepo.GetHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if rates == nil {
+		rates = []models.KeyRate{}
+	}
+
+	return rates, nil
+}
+
+// RefreshKeyRateHistory загружает историю ставки с последней сохраненной даты (при первом
+// запуске - с начала истории) и возвращает число загруженных записей. Последняя
+// сохраненная дата запрашивается повторно, так как ЦБ РФ мог уточнить ставку.
+func (s *cbrService) RefreshKeyRateHistory(ctx context.Context) (int, error) {
+	from := keyRateHistoryStart
+
+	last, err := s.keyRateRepo.GetLastHistoryDate()
+	if err != nil {
+		return 0, err
+	}
+	if last != nil {
+		from = *last
+	}
+
+	rates, err := s.fetchWithRetry(ctx, from, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.keyRateRepo.SaveHistory(rates); err != nil {
+		return 0, err
+	}
+
+	return len(rates), nil
+}
+
+// fetchWithRetry запрашивает ставки у ЦБ РФ за период, повторяя запрос после ошибки
+// до Retries раз с удваивающейся паузой
+func (s *cbrService) fetchWithRetry(ctx context.Context, from, to time.Time) ([]models.KeyRate, error) {
+	backoff := s.config.RetryBackoff
+
+	var lastErr error
+	for attempt := 0; attempt <= s.config.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		rates, err := s.fetch(ctx, from, to)
+		if err == nil {
+			return rates, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+// fetch запрашивает метод KeyRateXML и возвращает ставки за период в порядке возрастания дат
+func (s *cbrService) fetch(ctx context.Context, from, to time.Time) ([]models.KeyRate, error) {
+	soapRequest := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
 <soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema">
   <soap:Body>
-    <KeyRateXML xmlns="http://web.cbr.ru/" />
+    <KeyRateXML xmlns="http://web.cbr.ru/">
+      <fromDate>%s</fromDate>
+      <ToDate>%s</ToDate>
+    </KeyRateXML>
   </soap:Body>
-</soap:Envelope>`
+</soap:Envelope>`, from.Format(soapDateLayout), to.Format(soapDateLayout))
 
-	req, err := http.NewRequest("POST", s.soapURL, bytes.NewBufferString(soapRequest))
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.config.URL, bytes.NewBufferString(soapRequest))
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
 	req.Header.Set("SOAPAction", "http://web.cbr.ru/KeyRateXML")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cbr responded with status %d", resp.StatusCode)
+	}
+
+	rates, err := parseKeyRates(body)
+	if err != nil {
+		return nil, err
 	}
 
+	fetchedAt := time.Now()
+	for i := range rates {
+		rates[i].FetchedAt = fetchedAt
+	}
+
+	return rates, nil
+}
+
+// parseKeyRates разбирает ответ KeyRateXML: записи KR с датой DT и ставкой Rate
+func parseKeyRates(body []byte) ([]models.KeyRate, error) {
 	doc := etree.NewDocument()
 	if err := doc.ReadFromBytes(body); err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	keyRateElements := doc.FindElements("//KeyRate")
-
-	if len(keyRateElements) == 0 {
-		return 0, fmt.Errorf("key rate not found in response")
+	if fault := doc.FindElement("//Fault"); fault != nil {
+		message := "unknown fault"
+		if faultString := fault.FindElement("faultstring"); faultString != nil {
+			message = faultString.Text()
+		}
+		return nil, fmt.Errorf("cbr soap fault: %s", message)
 	}
 
-	latestElement := keyRateElements[len(keyRateElements)-1]
-	rateStr := latestElement.SelectElement("Rate").Text()
+	var rates []models.KeyRate
+	for _, element := range doc.FindElements("//KR") {
+		dateElement := element.SelectElement("DT")
+		rateElement := element.SelectElement("Rate")
+		if dateElement == nil || rateElement == nil {
+			return nil, fmt.Errorf("key rate record without DT or Rate")
+		}
 
-	rateStr = strings.Replace(rateStr, ",", ".", 1)
-	rate, err := strconv.ParseFloat(rateStr, 64)
-	if err != nil {
-		return 0, err
+		dt, err := time.Parse(time.RFC3339, strings.TrimSpace(dateElement.Text()))
+		if err != nil {
+			return nil, fmt.Errorf("invalid key rate date: %w", err)
+		}
+
+		rateStr := strings.Replace(strings.TrimSpace(rateElement.Text()), ",", ".", 1)
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key rate value: %w", err)
+		}
+
+		rates = append(rates, models.KeyRate{
+			Rate: rate,
+			Date: time.Date(dt.Year(), dt.Month(), dt.Day(), 0, 0, 0, 0, time.Local),
+		})
 	}
 
-	return rate, nil
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("key rate not found in response")
+	}
+
+	// Порядок записей в ответе не гарантирован
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Date.Before(rates[j].Date) })
+
+	return rates, nil
 }
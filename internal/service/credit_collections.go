@@ -0,0 +1,269 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+// Корзина, начиная с которой у заемщика блокируются карты и новые кредитные заявки
+const collectionsFreezeBucket = models.CollectionBucket61To90
+
+// overdueCredit - просроченная задолженность по кредиту на момент запуска
+type overdueCredit struct {
+	oldest models.PaymentSchedule
+	amount float64
+}
+
+// ProcessCollections пересчитывает дни просрочки по кредитам с просроченными платежами,
+// распределяет их по корзинам и при переходе в следующую корзину применяет меры
+// воздействия. Дела по кредитам без просрочки закрываются.
+func (s *creditService) ProcessCollections() error {
+	overduePayments, err := s.paymentRepo.GetOverduePayments()
+	if err != nil {
+		return err
+	}
+
+	// Платежи упорядочены по дате, поэтому первый платеж кредита - самый ранний
+	overdue := make(map[int64]*overdueCredit)
+	for _, payment := range overduePayments {
+		if _, ok := overdue[payment.CreditID]; !ok {
+			overdue[payment.CreditID] = &overdueCredit{oldest: payment}
+		}
+		overdue[payment.CreditID].amount += payment.Outstanding()
+	}
+
+	openCases, err := s.collectionRepo.GetOpenCases()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	cases := make(map[int64]models.CollectionCase)
+	for _, collectionCase := range openCases {
+		if _, ok := overdue[collectionCase.CreditID]; !ok {
+			s.resolveCollectionCase(collectionCase, now)
+			continue
+		}
+		cases[collectionCase.CreditID] = collectionCase
+	}
+
+	for creditID, debt := range overdue {
+		credit, err := s.creditRepo.GetByID(creditID)
+		if err != nil {
+			continue
+		}
+
+		daysPastDue := int(now.Sub(debt.oldest.PaymentDate).Hours() / 24)
+		bucket := models.CollectionBucketFor(daysPastDue)
+
+		collectionCase, ok := cases[creditID]
+		if !ok {
+			collectionCase = models.CollectionCase{
+				CreditID:     credit.ID,
+				UserID:       credit.UserID,
+				OverdueSince: debt.oldest.PaymentDate,
+				Status:       models.CollectionCaseOpen,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			}
+		}
+
+		collectionCase.DaysPastDue = daysPastDue
+		collectionCase.Bucket = bucket
+		collectionCase.OverdueAmount = math.Round(debt.amount*100) / 100
+
+		if !ok {
+			id, err := s.collectionRepo.Create(collectionCase)
+			if err != nil {
+				continue
+			}
+			collectionCase.ID = id
+		}
+
+		// Меры пропущенных корзин применяются тоже, например при запуске после перерыва.
+		// Меры каждой корзины применяются по делу один раз.
+		for _, next := range models.CollectionBuckets {
+			if next.Level() > collectionCase.EscalatedBucket.Level() && next.Level() <= bucket.Level() {
+				s.escalate(&collectionCase, next, debt.oldest, now)
+				collectionCase.EscalatedBucket = next
+			}
+		}
+
+		// Блокировка, которая не удалась при переходе в корзину, повторяется при следующих запусках
+		if !collectionCase.CardsBlocked && collectionCase.EscalatedBucket.Level() >= collectionsFreezeBucket.Level() {
+			s.blockCards(&collectionCase, now)
+		}
+
+		s.collectionRepo.Update(collectionCase)
+	}
+
+	return nil
+}
+
+// escalate применяет меры, предусмотренные для корзины, и записывает их в историю дела
+func (s *creditService) escalate(collectionCase *models.CollectionCase, bucket models.CollectionBucket, oldest models.PaymentSchedule, now time.Time) {
+	var action models.CollectionAction
+	var notice string
+
+	switch bucket {
+	case models.CollectionBucket1To30:
+		action = models.CollectionActionReminder
+		notice = "Просим погасить просроченную задолженность в ближайшее время."
+	case models.CollectionBucket31To60:
+		action = models.CollectionActionEscalationFine
+		fine := math.Round(collectionCase.OverdueAmount*s.config.CollectionsFineRate*100) / 100
+		if fine > 0 {
+			s.penaltyRepo.Create(models.CreditPenalty{
+				CreditID:          collectionCase.CreditID,
+				PaymentScheduleID: oldest.ID,
+				Type:              models.PenaltyTypeFine,
+				Amount:            fine,
+				AccrualDate:       now,
+				Status:            models.PenaltyStatusUnpaid,
+				CreatedAt:         now,
+				UpdatedAt:         now,
+			})
+		}
+		notice = fmt.Sprintf("Начислен дополнительный штраф %.2f руб., пени начисляются в повышенном размере.", fine)
+	case models.CollectionBucket61To90:
+		// Карты блокируются в blockCards, чтобы неудачную блокировку можно было повторить
+		return
+	case models.CollectionBucket90Plus:
+		action = models.CollectionActionFinalNotice
+		notice = "Требуем погасить задолженность. В противном случае банк будет вынужден обратиться в суд."
+	default:
+		return
+	}
+
+	s.collectionRepo.AddAction(models.CollectionCaseAction{
+		CaseID:    collectionCase.ID,
+		Bucket:    bucket,
+		Action:    action,
+		Details:   notice,
+		CreatedAt: now,
+	})
+
+	go s.emailService.SendCollectionNoticeEmail(collectionCase.UserID, collectionCase.CreditID, collectionCase.DaysPastDue, collectionCase.OverdueAmount, notice)
+}
+
+// blockCards блокирует карты заемщика и записывает меру в историю дела. При ошибке
+// CardsBlocked остается false, и блокировка повторяется при следующем запуске.
+func (s *creditService) blockCards(collectionCase *models.CollectionCase, now time.Time) {
+	if err := s.cardRepo.BlockForCollections(collectionCase.UserID); err != nil {
+		return
+	}
+	collectionCase.CardsBlocked = true
+
+	notice := "Ваши карты заблокированы, прием новых кредитных заявок приостановлен до погашения задолженности."
+
+	s.collectionRepo.AddAction(models.CollectionCaseAction{
+		CaseID:    collectionCase.ID,
+		Bucket:    collectionsFreezeBucket,
+		Action:    models.CollectionActionCardsBlocked,
+		Details:   notice,
+		CreatedAt: now,
+	})
+
+	go s.emailService.SendCollectionNoticeEmail(collectionCase.UserID, collectionCase.CreditID, collectionCase.DaysPastDue, collectionCase.OverdueAmount, notice)
+}
+
+// resolveCollectionCase закрывает дело по погашенной просрочке. Карты разблокируются,
+// если у заемщика не осталось других дел, по которым они заблокированы.
+func (s *creditService) resolveCollectionCase(collectionCase models.CollectionCase, now time.Time) {
+	collectionCase.Status = models.CollectionCaseResolved
+	collectionCase.ResolvedAt = &now
+	collectionCase.DaysPastDue = 0
+	collectionCase.OverdueAmount = 0
+
+	if err := s.collectionRepo.Update(collectionCase); err != nil {
+		return
+	}
+
+	s.collectionRepo.AddAction(models.CollectionCaseAction{
+		CaseID:    collectionCase.ID,
+		Bucket:    collectionCase.Bucket,
+		Action:    models.CollectionActionResolved,
+		CreatedAt: now,
+	})
+
+	if !collectionCase.CardsBlocked {
+		return
+	}
+
+	remaining, err := s.collectionRepo.GetOpenCasesByUserID(collectionCase.UserID)
+	if err != nil {
+		return
+	}
+
+	for _, other := range remaining {
+		if other.CardsBlocked {
+			return
+		}
+	}
+
+	s.cardRepo.UnblockAfterCollections(collectionCase.UserID)
+}
+
+// applicationsFrozen проверяет, приостановлен ли прием кредитных заявок от заемщика.
+// Как и блокировка карт, приостановка действует до закрытия дела, даже если просрочка
+// сократилась и дело вернулось в младшую корзину.
+func (s *creditService) applicationsFrozen(userID int64) (bool, error) {
+	cases, err := s.collectionRepo.GetOpenCasesByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, collectionCase := range cases {
+		if collectionCase.EscalatedBucket.Level() >= collectionsFreezeBucket.Level() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// penaltyDailyRate возвращает ставку пени для кредита: при просрочке более 30 дней
+// ставка умножается на CollectionsPenaltyMultiplier
+func (s *creditService) penaltyDailyRate(bucket models.CollectionBucket) float64 {
+	if bucket.Level() >= models.CollectionBucket31To60.Level() && s.config.CollectionsPenaltyMultiplier > 0 {
+		return s.config.PenaltyDailyRate * s.config.CollectionsPenaltyMultiplier
+	}
+	return s.config.PenaltyDailyRate
+}
+
+// GetCollectionQueue возвращает открытые дела по просроченным кредитам, сгруппированные
+// по корзинам. Если bucket задан, возвращается только эта корзина.
+func (s *creditService) GetCollectionQueue(bucket models.CollectionBucket) ([]models.CollectionBucketSummary, error) {
+	if bucket != "" && !bucket.IsValid() {
+		return nil, ErrInvalidCollectionBucket
+	}
+
+	cases, err := s.collectionRepo.GetOpenCases()
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []models.CollectionBucketSummary
+	for _, b := range models.CollectionBuckets {
+		if bucket != "" && b != bucket {
+			continue
+		}
+
+		summary := models.CollectionBucketSummary{Bucket: b, Cases: []models.CollectionCase{}}
+		for _, collectionCase := range cases {
+			if collectionCase.Bucket == b {
+				summary.Cases = append(summary.Cases, collectionCase)
+				summary.Count++
+				summary.OverdueAmount += collectionCase.OverdueAmount
+			}
+		}
+		summary.OverdueAmount = math.Round(summary.OverdueAmount*100) / 100
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+// RepriceFloatingCredits пересчитывает кредиты с плавающей ставкой, если ключевая ставка
+// ЦБ РФ отличается от той, по которой рассчитана текущая ставка кредита. Если ставку
+// получить не удалось или ЦБ РФ не ответил и ставка устарела, кредиты не пересчитываются.
+// Ошибка пересчета одного кредита не останавливает остальные: ошибки возвращаются вместе
+// с номерами кредитов.
+func (s *creditService) RepriceFloatingCredits() error {
+	rate, err := s.cbrService.GetKeyRate(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get key rate: %w", err)
+	}
+
+	if rate.Stale {
+		return fmt.Errorf("key rate is stale, last fetched at %s", rate.FetchedAt.Format(time.RFC3339))
+	}
+
+	keyRate := rate.Rate
+
+	credits, err := s.creditRepo.GetActiveCredits()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, credit := range credits {
+		if !credit.IsFloating || math.Abs(credit.KeyRate-keyRate) < 0.005 {
+			continue
+		}
+
+		if err := s.repriceCredit(credit, keyRate); err != nil {
+			errs = append(errs, fmt.Errorf("credit %d: %w", credit.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// repriceCredit пересчитывает по новой ставке остаток графика начиная со следующего
+// платежа. Наступившие, но не оплаченные платежи остаются без изменений.
+func (s *creditService) repriceCredit(credit models.Credit, keyRate float64) error {
+	schedules, err := s.paymentRepo.GetByCreditID(credit.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	remaining, ok := splitSchedule(credit, schedules, now)
+	if !ok {
+		return nil
+	}
+
+	credit.KeyRate = keyRate
+	credit.KeyRateStale = false
+	credit.InterestRate = creditRate(credit, keyRate)
+
+	newSchedules := s.rebuildSchedule(credit, remaining.principal, remaining.firstMonth, remaining.count, remaining.accruedFrom)
+
+	credit.MonthlyPayment = newSchedules[0].Amount
+	credit.TotalPayment = remaining.keptTotal + totalScheduled(newSchedules)
+
+	tx, err := s.creditRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.paymentRepo.CancelPendingFromTx(tx, credit.ID, remaining.next.PaymentDate); err != nil {
+		return err
+	}
+
+	if err := s.paymentRepo.CreateBatchTx(tx, newSchedules); err != nil {
+		return err
+	}
+
+	if err := s.creditRepo.UpdateTx(tx, credit); err != nil {
+		return err
+	}
+
+	if err := s.creditRepo.AddRateHistoryTx(tx, models.CreditRateHistory{
+		CreditID:       credit.ID,
+		KeyRate:        keyRate,
+		InterestRate:   credit.InterestRate,
+		MonthlyPayment: credit.MonthlyPayment,
+		EffectiveFrom:  remaining.next.PaymentDate,
+		Reason:         "key rate changed",
+		ChangedAt:      now,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	go s.emailService.SendCreditRateChangeEmail(credit.UserID, credit.ID, credit.InterestRate, credit.MonthlyPayment, remaining.next.PaymentDate)
+
+	return nil
+}
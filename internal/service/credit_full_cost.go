@@ -0,0 +1,115 @@
+package service
+
+import (
+	"math"
+
+	"bank-service/internal/models"
+)
+
+// Число базовых периодов в году: платежи ежемесячные, базовый период - месяц
+const fullCostBasePeriodsPerYear = 12
+
+// fullCostOfCredit рассчитывает полную стоимость кредита по формуле 353-ФЗ:
+// ПСК = i * ЧБП * 100, где i - ставка базового периода, при которой приведенная
+// стоимость всех денежных потоков (выдача, комиссии, платежи) равна нулю.
+// Возвращает ПСК в процентах годовых и в денежном выражении.
+func fullCostOfCredit(credit models.Credit, schedules []models.PaymentSchedule) (float64, float64) {
+	type cashFlow struct {
+		amount float64
+		q      float64 // число полных базовых периодов
+		e      float64 // остаток в долях базового периода
+	}
+
+	// Комиссия за выдачу уменьшает фактически полученную заемщиком сумму
+	flows := []cashFlow{{amount: -(credit.Amount - credit.IssuanceFee)}}
+
+	paymentsTotal := 0.0
+	for _, schedule := range schedules {
+		q, periodStart := models.WholeMonthsBetween(credit.StartDate, schedule.PaymentDate)
+		periodEnd := models.AddMonths(credit.StartDate, q+1)
+		e := schedule.PaymentDate.Sub(periodStart).Hours() / periodEnd.Sub(periodStart).Hours()
+
+		flows = append(flows, cashFlow{amount: schedule.Amount, q: float64(q), e: e})
+		paymentsTotal += schedule.Amount
+	}
+
+	presentValue := func(i float64) float64 {
+		sum := 0.0
+		for _, flow := range flows {
+			sum += flow.amount / ((1 + flow.e*i) * math.Pow(1+i, flow.q))
+		}
+		return sum
+	}
+
+	// Приведенная стоимость убывает по i, корень ищется делением отрезка пополам
+	low, high := 0.0, 1.0
+	if presentValue(low) <= 0 {
+		low, high = -0.5, 0.0
+	}
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		if presentValue(mid) > 0 {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	rate := (low + high) / 2 * fullCostBasePeriodsPerYear * 100
+	rate = math.Round(rate*1000) / 1000
+
+	amount := paymentsTotal + credit.IssuanceFee - credit.Amount
+
+	return rate, math.Round(amount*100) / 100
+}
+
+// BackfillFullCost рассчитывает и сохраняет ПСК кредитов, выданных до того, как она стала
+// рассчитываться при выдаче
+func (s *creditService) BackfillFullCost() error {
+	credits, err := s.creditRepo.GetWithoutFullCost()
+	if err != nil {
+		return err
+	}
+
+	for i := range credits {
+		s.fillFullCost(&credits[i])
+	}
+
+	return nil
+}
+
+// fillFullCost рассчитывает ПСК кредита, если она не была сохранена: по графику платежей
+// без отмененных строк, а для заявки без графика - по графику, который был бы построен
+// при выдаче. Рассчитанная ПСК сохраняется, чтобы не пересчитывать ее при каждом чтении.
+func (s *creditService) fillFullCost(credit *models.Credit) {
+	if credit.FullCostRate != 0 || credit.FullCostAmount != 0 || credit.Amount <= 0 || credit.Term <= 0 {
+		return
+	}
+
+	schedules, err := s.paymentRepo.GetByCreditID(credit.ID)
+	if err != nil {
+		return
+	}
+
+	var flows []models.PaymentSchedule
+	for _, schedule := range schedules {
+		if schedule.Status != models.PaymentStatusCanceled {
+			flows = append(flows, schedule)
+		}
+	}
+
+	priced := *credit
+	if priced.StartDate.IsZero() {
+		priced.StartDate = priced.CreatedAt
+	}
+
+	if len(flows) == 0 {
+		if flows, err = s.generatePaymentSchedule(priced); err != nil || len(flows) == 0 {
+			return
+		}
+	}
+
+	credit.FullCostRate, credit.FullCostAmount = fullCostOfCredit(priced, flows)
+
+	s.creditRepo.UpdateFullCost(credit.ID, credit.FullCostRate, credit.FullCostAmount)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+// periodInterest возвращает проценты на principal за период с from по to по базе начисления кредита
+func periodInterest(credit models.Credit, principal float64, from, to time.Time) float64 {
+	return principal * credit.InterestRate / 100 * credit.DayCount.YearFraction(from, to)
+}
+
+// interestOwed рассчитывает проценты к уплате на дату asOf: проценты, начисленные с даты
+// последнего наступившего платежа на остаток основного долга, и неоплаченные проценты
+// наступивших платежей
+func interestOwed(credit models.Credit, schedules []models.PaymentSchedule, asOf time.Time) models.InterestOwed {
+	owed := models.InterestOwed{
+		CreditID:     credit.ID,
+		AsOf:         asOf,
+		DayCount:     credit.DayCount,
+		InterestRate: credit.InterestRate,
+		PeriodStart:  credit.StartDate,
+	}
+
+	for _, schedule := range schedules {
+		if schedule.Status == models.PaymentStatusCanceled {
+			continue
+		}
+
+		unpaid := schedule.Status == models.PaymentStatusPending || schedule.Status == models.PaymentStatusOverdue
+		// Частично внесенная сумма гасит сначала проценты, затем основной долг
+		principal := schedule.Principal - math.Max(schedule.PaidAmount-schedule.Interest, 0)
+
+		if !schedule.PaymentDate.After(asOf) {
+			if schedule.PaymentDate.After(owed.PeriodStart) {
+				owed.PeriodStart = schedule.PaymentDate
+			}
+			if unpaid {
+				owed.UnpaidInterest += math.Max(schedule.Interest-schedule.PaidAmount, 0)
+				owed.OutstandingPrincipal += principal
+			}
+			continue
+		}
+
+		if unpaid {
+			owed.Principal += principal
+			owed.OutstandingPrincipal += principal
+		}
+	}
+
+	owed.AccruedInterest = math.Round(periodInterest(credit, owed.Principal, owed.PeriodStart, asOf)*100) / 100
+	owed.TotalInterest = owed.AccruedInterest + owed.UnpaidInterest
+	owed.PayoffAmount = owed.OutstandingPrincipal + owed.TotalInterest
+
+	return owed
+}
+
+// GetInterestOwed возвращает проценты к уплате и сумму полного погашения на дату asOf
+// вместе с ежедневными начислениями текущего процентного периода
+func (s *creditService) GetInterestOwed(creditID int64, userID int64, asOf time.Time) (models.InterestOwed, error) {
+	credit, err := s.creditRepo.GetByID(creditID)
+	if err != nil {
+		return models.InterestOwed{}, ErrCreditNotFound
+	}
+
+	if credit.UserID != userID {
+		return models.InterestOwed{}, ErrCreditAccessDenied
+	}
+
+	schedules, err := s.paymentRepo.GetByCreditID(creditID)
+	if err != nil {
+		return models.InterestOwed{}, err
+	}
+
+	owed := interestOwed(credit, schedules, asOf)
+
+	owed.DailyAccruals, err = s.creditRepo.GetInterestAccruals(creditID, startOfDay(owed.PeriodStart), asOf)
+	if err != nil {
+		return models.InterestOwed{}, err
+	}
+
+	return owed, nil
+}
+
+// AccrueDailyInterest начисляет проценты по действующим кредитам за каждый день с
+// последнего начисления (или с даты выдачи) по сегодняшний день включительно
+func (s *creditService) AccrueDailyInterest() error {
+	credits, err := s.creditRepo.GetActiveCredits()
+	if err != nil {
+		return err
+	}
+
+	today := startOfDay(time.Now())
+
+	for _, credit := range credits {
+		last, err := s.creditRepo.GetLastAccrualDate(credit.ID)
+		if err != nil {
+			continue
+		}
+
+		from := startOfDay(credit.StartDate)
+		if last != nil {
+			from = time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.Local)
+		}
+
+		if !from.Before(today) {
+			continue
+		}
+
+		schedules, err := s.paymentRepo.GetByCreditID(credit.ID)
+		if err != nil {
+			continue
+		}
+
+		for day := from.AddDate(0, 0, 1); !day.After(today); day = day.AddDate(0, 0, 1) {
+			previous := day.AddDate(0, 0, -1)
+			principal := interestOwed(credit, schedules, previous).Principal
+
+			if err := s.creditRepo.AddInterestAccrual(models.CreditInterestAccrual{
+				CreditID:     credit.ID,
+				AccrualDate:  day,
+				Principal:    principal,
+				InterestRate: credit.InterestRate,
+				DayCount:     credit.DayCount,
+				Amount:       periodInterest(credit, principal, previous, day),
+				CreatedAt:    time.Now(),
+			}); err != nil {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"bank-service/internal/config"
+	"bank-service/internal/models"
+	"bank-service/internal/repository"
+)
+
+var (
+	ErrNotCreditAccount   = errors.New("credit line is available only for CREDIT accounts")
+	ErrInvalidCreditLimit = errors.New("credit limit must not be negative or below the used amount")
+)
+
+// CreditLineService обслуживает возобновляемые кредитные линии на счетах CREDIT:
+// ежедневно начисляет проценты на использованную часть лимита, раз в месяц формирует
+// выписку с минимальным платежом и контролирует его внесение.
+type CreditLineService interface {
+	SetCreditLimit(accountID int64, creditLimit float64) (models.AccountResponse, error)
+	GetStatements(accountID int64, userID int64) ([]models.CreditLineStatement, error)
+	AccrueInterest() error
+	GenerateStatements() error
+	EnforceMinimumPayments() error
+}
+
+type creditLineService struct {
+	creditLineRepo  repository.CreditLineRepository
+	accountRepo     repository.AccountRepository
+	transactionRepo repository.TransactionRepository
+	holdRepo        repository.CardHoldRepository
+	emailService    EmailService
+	config          config.CreditLineConfig
+}
+
+func NewCreditLineService(
+	creditLineRepo repository.CreditLineRepository,
+	accountRepo repository.AccountRepository,
+	transactionRepo repository.TransactionRepository,
+	holdRepo repository.CardHoldRepository,
+	emailService EmailService,
+	cfg config.CreditLineConfig,
+) CreditLineService {
+	return &creditLineService{
+		creditLineRepo:  creditLineRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		holdRepo:        holdRepo,
+		emailService:    emailService,
+		config:          cfg,
+	}
+}
+
+// SetCreditLimit устанавливает кредитный лимит счета. Лимит нельзя снизить ниже уже
+// использованной суммы.
+func (s *creditLineService) SetCreditLimit(accountID int64, creditLimit float64) (models.AccountResponse, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return models.AccountResponse{}, ErrAccountNotFound
+	}
+
+	if account.Type != models.AccountTypeCredit {
+		return models.AccountResponse{}, ErrNotCreditAccount
+	}
+
+	if creditLimit < 0 || creditLimit < account.UsedCredit() {
+		return models.AccountResponse{}, ErrInvalidCreditLimit
+	}
+
+	if err := s.accountRepo.UpdateCreditLimit(account.ID, creditLimit); err != nil {
+		return models.AccountResponse{}, err
+	}
+
+	account.CreditLimit = creditLimit
+
+	return models.ToAccountResponse(account), nil
+}
+
+func (s *creditLineService) GetStatements(accountID int64, userID int64) ([]models.CreditLineStatement, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+
+	if account.UserID != userID {
+		return nil, ErrAccountAccessDenied
+	}
+
+	if account.Type != models.AccountTypeCredit {
+		return nil, ErrNotCreditAccount
+	}
+
+	return s.creditLineRepo.GetStatementsByAccountID(accountID)
+}
+
+// balanceAt восстанавливает остаток счета на момент at по операциям, проведенным после него.
+// Активные холды уменьшают баланс счета, но операции по ним еще нет, поэтому их сумма
+// возвращается: проценты и выписки считаются только по проведенным операциям.
+func (s *creditLineService) balanceAt(account models.Account, at time.Time) (float64, error) {
+	debits, credits, err := s.transactionRepo.GetAccountTurnover(account.ID, at, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	held, err := s.holdRepo.GetActiveAmount(account.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	return account.Balance + held + debits - credits, nil
+}
+
+// AccrueInterest начисляет проценты за каждый завершившийся день на задолженность по
+// кредитной линии на конец дня. Пропущенные дни начисляются при следующем запуске.
+func (s *creditLineService) AccrueInterest() error {
+	accounts, err := s.accountRepo.GetCreditLineAccounts()
+	if err != nil {
+		return err
+	}
+
+	today := startOfDay(time.Now())
+
+	for _, account := range accounts {
+		day := startOfDay(account.CreatedAt)
+
+		last, err := s.creditLineRepo.GetLastAccrualDate(account.ID)
+		if err != nil {
+			continue
+		}
+		if last != nil {
+			day = startOfDay(*last).AddDate(0, 0, 1)
+		}
+
+		for ; day.Before(today); day = day.AddDate(0, 0, 1) {
+			balance, err := s.balanceAt(account, day.AddDate(0, 0, 1))
+			if err != nil {
+				break
+			}
+
+			used := math.Max(-balance, 0)
+			now := time.Now()
+			if err := s.creditLineRepo.AddAccrual(models.CreditLineAccrual{
+				AccountID:    account.ID,
+				AccrualDate:  day,
+				UsedAmount:   used,
+				InterestRate: s.config.InterestRate,
+				Amount:       used * s.config.InterestRate / 100 / 365,
+				CreatedAt:    now,
+			}); err != nil {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// GenerateStatements закрывает месячный период кредитной линии и формирует выписку.
+// Проценты за период фиксируются в выписке и списываются, только если задолженность
+// не будет погашена полностью до даты минимального платежа (льготный период).
+func (s *creditLineService) GenerateStatements() error {
+	accounts, err := s.accountRepo.GetCreditLineAccounts()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, account := range accounts {
+		last, err := s.creditLineRepo.GetLastStatement(account.ID)
+		if err != nil {
+			continue
+		}
+
+		periodStart := startOfDay(account.CreatedAt)
+		if last != nil {
+			periodStart = last.PeriodEnd
+		}
+
+		periodEnd := periodStart.AddDate(0, 1, 0)
+		if periodEnd.After(now) {
+			continue
+		}
+
+		if err := s.generateStatement(account, last, periodStart, periodEnd); err != nil {
+			continue
+		}
+	}
+
+	return nil
+}
+
+func (s *creditLineService) generateStatement(account models.Account, last *models.CreditLineStatement, periodStart, periodEnd time.Time) error {
+	openingBalance, err := s.balanceAt(account, periodStart)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		openingBalance = last.ClosingBalance
+	}
+
+	closingBalance, err := s.balanceAt(account, periodEnd)
+	if err != nil {
+		return err
+	}
+
+	debits, credits, err := s.transactionRepo.GetAccountTurnover(account.ID, periodStart, periodEnd)
+	if err != nil {
+		return err
+	}
+
+	accrued, err := s.creditLineRepo.SumAccruals(account.ID, periodStart, periodEnd)
+	if err != nil {
+		return err
+	}
+
+	debt := math.Max(-closingBalance, 0)
+	minimumPayment := math.Min(debt, math.Max(debt*s.config.MinimumPaymentRate, s.config.MinimumPayment))
+
+	status := models.StatementStatusOpen
+	if debt == 0 {
+		status = models.StatementStatusPaid
+	}
+
+	now := time.Now()
+	statement := models.CreditLineStatement{
+		AccountID:       account.ID,
+		PeriodStart:     periodStart,
+		PeriodEnd:       periodEnd,
+		OpeningBalance:  openingBalance,
+		ClosingBalance:  closingBalance,
+		Debits:          debits,
+		Credits:         credits,
+		AccruedInterest: math.Round(accrued*100) / 100,
+		Debt:            debt,
+		MinimumPayment:  math.Round(minimumPayment*100) / 100,
+		DueDate:         periodEnd.AddDate(0, 0, s.config.PaymentDays),
+		Status:          status,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	tx, err := s.creditLineRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := s.creditLineRepo.CreateStatementTx(tx, statement); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if status == models.StatementStatusOpen {
+		go s.emailService.SendCreditLineStatementEmail(account.UserID, account.Number, statement.Debt, statement.MinimumPayment, statement.DueDate)
+	}
+
+	return nil
+}
+
+// EnforceMinimumPayments учитывает поступления на счет после закрытия выписки. Полное
+// погашение до даты платежа освобождает от процентов за период. После даты платежа
+// проценты списываются, а при невнесенном минимальном платеже начисляется штраф
+// и кредитный лимит приостанавливается до погашения минимального платежа.
+func (s *creditLineService) EnforceMinimumPayments() error {
+	statements, err := s.creditLineRepo.GetUnsettledStatements()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, statement := range statements {
+		account, err := s.accountRepo.GetByID(statement.AccountID)
+		if err != nil {
+			continue
+		}
+
+		_, paid, err := s.transactionRepo.GetAccountTurnover(account.ID, statement.PeriodEnd, now)
+		if err != nil {
+			continue
+		}
+
+		if err := s.settleStatement(account, statement, paid, now); err != nil {
+			continue
+		}
+	}
+
+	return nil
+}
+
+func (s *creditLineService) settleStatement(account models.Account, statement models.CreditLineStatement, paid float64, now time.Time) error {
+	previousStatus := statement.Status
+	statement.PaidAmount = paid
+	pastDue := now.After(statement.DueDate)
+
+	switch {
+	case previousStatus == models.StatementStatusOpen && paid >= statement.Debt-0.005:
+		statement.Status = models.StatementStatusPaid
+	case paid >= statement.MinimumPayment-0.005:
+		if previousStatus == models.StatementStatusOpen && !pastDue {
+			break
+		}
+		statement.Status = models.StatementStatusMinimumPaid
+	case pastDue:
+		statement.Status = models.StatementStatusOverdue
+	}
+
+	tx, err := s.creditLineRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Проценты за период списываются, если задолженность не погашена полностью в срок
+	if previousStatus == models.StatementStatusOpen && statement.Status != models.StatementStatusOpen &&
+		statement.Status != models.StatementStatusPaid && statement.AccruedInterest > 0 {
+		statement.Interest = statement.AccruedInterest
+		description := fmt.Sprintf("Credit line interest for statement %d", statement.ID)
+		if err := s.chargeTx(tx, &account, statement.Interest, description, now); err != nil {
+			return err
+		}
+	}
+
+	if previousStatus == models.StatementStatusOpen && statement.Status == models.StatementStatusOverdue {
+		statement.LateFee = s.config.LateFee
+		if statement.LateFee > 0 {
+			description := fmt.Sprintf("Credit line late fee for statement %d", statement.ID)
+			if err := s.chargeTx(tx, &account, statement.LateFee, description, now); err != nil {
+				return err
+			}
+		}
+
+		if err := s.accountRepo.SetCreditLineSuspendedTx(tx, account.ID, true); err != nil {
+			return err
+		}
+	}
+
+	if previousStatus == models.StatementStatusOverdue && statement.Status == models.StatementStatusMinimumPaid {
+		if err := s.accountRepo.SetCreditLineSuspendedTx(tx, account.ID, false); err != nil {
+			return err
+		}
+	}
+
+	if err := s.creditLineRepo.UpdateStatementTx(tx, statement); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if previousStatus == models.StatementStatusOpen && statement.Status == models.StatementStatusOverdue {
+		go s.emailService.SendCreditLineOverdueEmail(account.UserID, account.Number, statement.MinimumPayment, statement.LateFee)
+	}
+
+	return nil
+}
+
+// chargeTx списывает проценты или штраф по кредитной линии со счета
+func (s *creditLineService) chargeTx(tx *sql.Tx, account *models.Account, amount float64, description string, now time.Time) error {
+	account.Balance -= amount
+	if err := s.accountRepo.UpdateBalanceTx(tx, account.ID, account.Balance); err != nil {
+		return err
+	}
+
+	transaction := models.Transaction{
+		UserID:          account.UserID,
+		FromAccountID:   &account.ID,
+		Type:            models.TransactionTypePayment,
+		Amount:          amount,
+		Description:     description,
+		Status:          models.TransactionStatusCompleted,
+		TransactionDate: now,
+		CreatedAt:       now,
+	}
+
+	_, err := s.transactionRepo.CreateTx(tx, transaction)
+	return err
+}
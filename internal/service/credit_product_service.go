@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bank-service/internal/models"
+	"bank-service/internal/repository"
+)
+
+var (
+	ErrCreditProductNotFound = errors.New("credit product not found")
+	ErrInvalidCreditProduct  = errors.New("invalid credit product")
+)
+
+type CreditProductService interface {
+	GetActive() ([]models.CreditProduct, error)
+	GetAll() ([]models.CreditProduct, error)
+	Create(input models.CreditProductInput) (models.CreditProduct, error)
+	Update(id int64, input models.CreditProductInput) (models.CreditProduct, error)
+}
+
+type creditProductService struct {
+	productRepo repository.CreditProductRepository
+}
+
+func NewCreditProductService(productRepo repository.CreditProductRepository) CreditProductService {
+	return &creditProductService{
+		productRepo: productRepo,
+	}
+}
+
+func (s *creditProductService) GetActive() ([]models.CreditProduct, error) {
+	return s.productRepo.GetAll(true)
+}
+
+func (s *creditProductService) GetAll() ([]models.CreditProduct, error) {
+	return s.productRepo.GetAll(false)
+}
+
+func (s *creditProductService) Create(input models.CreditProductInput) (models.CreditProduct, error) {
+	if err := validateCreditProduct(input); err != nil {
+		return models.CreditProduct{}, err
+	}
+
+	now := time.Now()
+	product := applyCreditProductInput(models.CreditProduct{CreatedAt: now, UpdatedAt: now}, input)
+
+	id, err := s.productRepo.Create(product)
+	if err != nil {
+		return models.CreditProduct{}, err
+	}
+
+	product.ID = id
+
+	return product, nil
+}
+
+func (s *creditProductService) Update(id int64, input models.CreditProductInput) (models.CreditProduct, error) {
+	product, err := s.productRepo.GetByID(id)
+	if err != nil {
+		return models.CreditProduct{}, ErrCreditProductNotFound
+	}
+
+	if err := validateCreditProduct(input); err != nil {
+		return models.CreditProduct{}, err
+	}
+
+	product = applyCreditProductInput(product, input)
+	product.UpdatedAt = time.Now()
+
+	if err := s.productRepo.Update(product); err != nil {
+		return models.CreditProduct{}, err
+	}
+
+	return product, nil
+}
+
+func validateCreditProduct(input models.CreditProductInput) error {
+	switch {
+	case strings.TrimSpace(input.Code) == "" || strings.TrimSpace(input.Name) == "":
+		return fmt.Errorf("%w: code and name are required", ErrInvalidCreditProduct)
+	case input.MinAmount <= 0 || input.MinAmount > input.MaxAmount:
+		return fmt.Errorf("%w: amount limits must satisfy 0 < min_amount <= max_amount", ErrInvalidCreditProduct)
+	case input.MinTerm <= 0 || input.MinTerm > input.MaxTerm:
+		return fmt.Errorf("%w: term limits must satisfy 0 < min_term <= max_term", ErrInvalidCreditProduct)
+	case input.DayCount != "" && !input.DayCount.IsValid():
+		return fmt.Errorf("%w: day_count must be 30/360, ACTUAL/365 or ACTUAL/ACTUAL", ErrInvalidCreditProduct)
+	case input.IssuanceFeeRate < 0 || input.IssuanceFeeFixed < 0:
+		return fmt.Errorf("%w: fees cannot be negative", ErrInvalidCreditProduct)
+	case input.MinIncome < 0 || input.MaxActiveCredits < 0:
+		return fmt.Errorf("%w: eligibility limits cannot be negative", ErrInvalidCreditProduct)
+	}
+
+	return nil
+}
+
+func applyCreditProductInput(product models.CreditProduct, input models.CreditProductInput) models.CreditProduct {
+	product.Code = strings.ToUpper(strings.TrimSpace(input.Code))
+	product.Name = input.Name
+	product.Description = input.Description
+	product.RateSpread = input.RateSpread
+	product.IsFloating = input.IsFloating
+	product.DayCount = input.DayCount
+	if product.DayCount == "" {
+		product.DayCount = models.DayCount30360 // Значение по умолчанию
+	}
+	product.MinAmount = input.MinAmount
+	product.MaxAmount = input.MaxAmount
+	product.MinTerm = input.MinTerm
+	product.MaxTerm = input.MaxTerm
+	product.IssuanceFeeRate = input.IssuanceFeeRate
+	product.IssuanceFeeFixed = input.IssuanceFeeFixed
+	product.MinIncome = input.MinIncome
+	product.MaxActiveCredits = input.MaxActiveCredits
+	product.IsActive = input.IsActive
+	return product
+}
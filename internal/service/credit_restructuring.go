@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"bank-service/internal/models"
+)
+
+var (
+	ErrInvalidRestructuring        = errors.New("restructuring type must be PAYMENT_HOLIDAY or EXTEND_TERM with a positive number of months")
+	ErrPaymentHolidayLimitExceeded = errors.New("payment holidays cannot exceed 6 months over the credit term")
+)
+
+// Максимальная суммарная длительность кредитных каникул по одному кредиту, в месяцах
+const maxPaymentHolidayMonths = 6
+
+// Restructure изменяет график действующего кредита по заявлению заемщика. Кредитные
+// каникулы переносят следующие платежи на months месяцев, а проценты за каникулы
+// капитализируются - прибавляются к основному долгу. Продление срока распределяет
+// остаток долга на months дополнительных месяцев. Неоплаченные будущие платежи
+// отменяются и заменяются новым графиком.
+func (s *creditService) Restructure(creditID int64, userID int64, request models.CreditRestructuringRequest) (models.CreditResponse, error) {
+	credit, err := s.creditRepo.GetByID(creditID)
+	if err != nil {
+		return models.CreditResponse{}, ErrCreditNotFound
+	}
+
+	if credit.UserID != userID {
+		return models.CreditResponse{}, ErrCreditAccessDenied
+	}
+
+	switch credit.Status {
+	case models.CreditStatusActive:
+	case models.CreditStatusOverdue:
+		return models.CreditResponse{}, ErrCreditHasOverdue
+	default:
+		return models.CreditResponse{}, ErrCreditNotRepayable
+	}
+
+	if request.Months <= 0 {
+		return models.CreditResponse{}, ErrInvalidRestructuring
+	}
+
+	switch request.Type {
+	case models.RestructuringPaymentHoliday:
+		restructurings, err := s.creditRepo.GetRestructurings(creditID)
+		if err != nil {
+			return models.CreditResponse{}, err
+		}
+
+		holidayMonths := request.Months
+		for _, restructuring := range restructurings {
+			if restructuring.Type == models.RestructuringPaymentHoliday {
+				holidayMonths += restructuring.Months
+			}
+		}
+
+		if holidayMonths > maxPaymentHolidayMonths {
+			return models.CreditResponse{}, ErrPaymentHolidayLimitExceeded
+		}
+	case models.RestructuringExtendTerm:
+		product, err := s.productRepo.GetByID(credit.ProductID)
+		if err != nil {
+			return models.CreditResponse{}, err
+		}
+
+		if credit.Term+request.Months > product.MaxTerm {
+			return models.CreditResponse{}, ErrInvalidCreditTerm
+		}
+	default:
+		return models.CreditResponse{}, ErrInvalidRestructuring
+	}
+
+	schedules, err := s.paymentRepo.GetByCreditID(creditID)
+	if err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	now := time.Now()
+
+	remaining, ok := splitSchedule(credit, schedules, now)
+	if !ok {
+		return models.CreditResponse{}, ErrCreditNotRepayable
+	}
+
+	restructuring := models.CreditRestructuring{
+		CreditID:             credit.ID,
+		Type:                 request.Type,
+		Months:               request.Months,
+		PrincipalBefore:      remaining.principal,
+		PrincipalAfter:       remaining.principal,
+		TermBefore:           credit.Term,
+		TermAfter:            credit.Term + request.Months,
+		MonthlyPaymentBefore: credit.MonthlyPayment,
+		Reason:               request.Reason,
+		CreatedAt:            now,
+	}
+
+	firstMonth := remaining.firstMonth
+	count := remaining.count
+	accruedFrom := remaining.accruedFrom
+
+	if request.Type == models.RestructuringPaymentHoliday {
+		// Проценты начисляются до даты последнего перенесенного платежа и прибавляются к долгу
+		holidayEnd := s.installmentDate(credit, firstMonth+request.Months-1)
+		restructuring.CapitalizedInterest = periodInterest(credit, remaining.principal, accruedFrom, holidayEnd)
+		restructuring.PrincipalAfter += restructuring.CapitalizedInterest
+
+		firstMonth += request.Months
+		accruedFrom = holidayEnd
+	} else {
+		count += request.Months
+	}
+
+	newSchedules := s.rebuildSchedule(credit, restructuring.PrincipalAfter, firstMonth, count, accruedFrom)
+
+	credit.Term = restructuring.TermAfter
+	credit.MonthlyPayment = newSchedules[0].Amount
+	credit.TotalPayment = remaining.keptTotal + totalScheduled(newSchedules)
+	credit.EndDate = newSchedules[len(newSchedules)-1].PaymentDate
+
+	restructuring.MonthlyPaymentAfter = credit.MonthlyPayment
+	restructuring.FirstPaymentDate = newSchedules[0].PaymentDate
+
+	tx, err := s.creditRepo.BeginTx()
+	if err != nil {
+		return models.CreditResponse{}, err
+	}
+	defer tx.Rollback()
+
+	if err := s.paymentRepo.CancelPendingFromTx(tx, credit.ID, remaining.next.PaymentDate); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	if err := s.paymentRepo.CreateBatchTx(tx, newSchedules); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	if err := s.creditRepo.UpdateTx(tx, credit); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	if err := s.creditRepo.AddRestructuringTx(tx, restructuring); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	go s.emailService.SendCreditRestructuringEmail(
+		credit.UserID,
+		credit.ID,
+		describeRestructuring(restructuring),
+		credit.MonthlyPayment,
+		restructuring.FirstPaymentDate,
+		credit.EndDate,
+	)
+
+	return models.ToCreditResponse(credit), nil
+}
+
+func (s *creditService) GetRestructurings(creditID int64, userID int64) ([]models.CreditRestructuring, error) {
+	credit, err := s.creditRepo.GetByID(creditID)
+	if err != nil {
+		return nil, ErrCreditNotFound
+	}
+
+	if credit.UserID != userID {
+		return nil, ErrCreditAccessDenied
+	}
+
+	return s.creditRepo.GetRestructurings(creditID)
+}
+
+func describeRestructuring(restructuring models.CreditRestructuring) string {
+	if restructuring.Type == models.RestructuringPaymentHoliday {
+		return fmt.Sprintf("кредитные каникулы на %d мес., капитализировано процентов: %.2f руб.", restructuring.Months, restructuring.CapitalizedInterest)
+	}
+	return fmt.Sprintf("срок кредита увеличен на %d мес.", restructuring.Months)
+}
@@ -1,10 +1,15 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"math"
 	"time"
 
+	"bank-service/internal/calendar"
+	"bank-service/internal/config"
 	"bank-service/internal/models"
 	"bank-service/internal/repository"
 )
@@ -13,48 +18,143 @@ var (
 	ErrCreditNotFound      = errors.New("credit not found")
 	ErrCreditAccessDenied  = errors.New("access to this credit is denied")
 	ErrInvalidCreditAmount = errors.New("credit amount must be positive")
-	ErrInvalidCreditTerm   = errors.New("credit term must be between 3 and 60 months")
+	ErrInvalidCreditTerm   = errors.New("credit term is outside the product limits")
+
+	ErrCreditAmountOutOfRange   = errors.New("credit amount is outside the product limits")
+	ErrCreditProductNotEligible = errors.New("borrower is not eligible for this credit product")
+
+	ErrInvalidDeclaredIncome = errors.New("declared income cannot be negative")
+	ErrInvalidRepaymentType  = errors.New("repayment type must be ANNUITY or DIFFERENTIATED")
+	ErrCreditNotPending      = errors.New("credit application is not awaiting review")
+	ErrInvalidCreditDecision = errors.New("decision must be APPROVED or REJECTED")
+
+	ErrInvalidCreditTransition = errors.New("credit status transition is not allowed")
+	ErrFullCostExceedsCap      = errors.New("full cost of credit exceeds the regulatory cap")
+	ErrInvalidQuoteRequest     = errors.New("quote requires between 1 and 20 term and repayment type combinations")
+
+	ErrCreditNotRepayable   = errors.New("credit has no outstanding installments")
+	ErrCreditHasOverdue     = errors.New("overdue installments must be paid before early repayment")
+	ErrInvalidRepaymentMode = errors.New("repayment mode must be REDUCE_TERM or REDUCE_PAYMENT")
+
+	ErrCreditApplicationsFrozen = errors.New("new credit applications are frozen until overdue debt is repaid")
+	ErrInvalidCollectionBucket  = errors.New("bucket must be DPD_1_30, DPD_31_60, DPD_61_90 or DPD_90_PLUS")
 )
 
+// Период, за который оценивается оборот по счетам заемщика
+const scoringTurnoverDays = 90
+
+// Продукт, который используется, если в заявке не указан product_id
+const defaultCreditProductCode = "CONSUMER"
+
+// Максимальное число вариантов в одном расчете кредита
+const maxQuoteVariants = 20
+
 type CreditService interface {
 	Apply(userID int64, application models.CreditApplication) (models.CreditResponse, error)
 	GetByID(id int64, userID int64) (models.CreditResponse, error)
 	GetByUserID(userID int64) ([]models.CreditResponse, error)
 	GetSchedule(creditID int64, userID int64) ([]models.PaymentScheduleResponse, error)
+	Quote(request models.CreditQuoteRequest) ([]models.CreditQuote, error)
+	GetStatusHistory(creditID int64, userID int64) ([]models.CreditStatusHistory, error)
+	GetRateHistory(creditID int64, userID int64) ([]models.CreditRateHistory, error)
+	GetReviewQueue() ([]models.CreditResponse, error)
+	Decide(creditID int64, request models.CreditDecisionRequest) (models.CreditResponse, error)
 	ProcessPendingPayments() error
+	AccruePenaltyInterest() error
+	RepriceFloatingCredits() error
+	AccrueDailyInterest() error
+	GetInterestOwed(creditID int64, userID int64, asOf time.Time) (models.InterestOwed, error)
+	Restructure(creditID int64, userID int64, request models.CreditRestructuringRequest) (models.CreditResponse, error)
+	GetRestructurings(creditID int64, userID int64) ([]models.CreditRestructuring, error)
+	ProcessCollections() error
+	BackfillFullCost() error
+	GetCollectionQueue(bucket models.CollectionBucket) ([]models.CollectionBucketSummary, error)
+	EarlyRepay(creditID int64, userID int64, request models.EarlyRepaymentRequest) (models.CreditResponse, error)
 }
 
 type creditService struct {
-	creditRepo   repository.CreditRepository
-	paymentRepo  repository.PaymentRepository
-	accountRepo  repository.AccountRepository
-	cbrService   CBRService
-	emailService EmailService
+	creditRepo      repository.CreditRepository
+	paymentRepo     repository.PaymentRepository
+	accountRepo     repository.AccountRepository
+	transactionRepo repository.TransactionRepository
+	penaltyRepo     repository.PenaltyRepository
+	productRepo     repository.CreditProductRepository
+	collectionRepo  repository.CollectionRepository
+	cardRepo        repository.CardRepository
+	cbrService      CBRService
+	emailService    EmailService
+	scoringEngine   ScoringEngine
+	calendar        *calendar.Calendar
+	config          config.CreditConfig
 }
 
 func NewCreditService(
 	creditRepo repository.CreditRepository,
 	paymentRepo repository.PaymentRepository,
 	accountRepo repository.AccountRepository,
+	transactionRepo repository.TransactionRepository,
+	penaltyRepo repository.PenaltyRepository,
+	productRepo repository.CreditProductRepository,
+	collectionRepo repository.CollectionRepository,
+	cardRepo repository.CardRepository,
 	cbrService CBRService,
 	emailService EmailService,
+	scoringEngine ScoringEngine,
+	calendar *calendar.Calendar,
+	config config.CreditConfig,
 ) CreditService {
 	return &creditService{
-		creditRepo:   creditRepo,
-		paymentRepo:  paymentRepo,
-		accountRepo:  accountRepo,
-		cbrService:   cbrService,
-		emailService: emailService,
+		creditRepo:      creditRepo,
+		paymentRepo:     paymentRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		penaltyRepo:     penaltyRepo,
+		productRepo:     productRepo,
+		collectionRepo:  collectionRepo,
+		cardRepo:        cardRepo,
+		cbrService:      cbrService,
+		emailService:    emailService,
+		scoringEngine:   scoringEngine,
+		calendar:        calendar,
+		config:          config,
 	}
 }
 
+// Apply регистрирует заявку в статусе PENDING и передает ее в скоринг. Одобренный
+// кредит сразу выдается, отклоненный сохраняется с причиной, а пограничный остается
+// в PENDING до решения оператора.
 func (s *creditService) Apply(userID int64, application models.CreditApplication) (models.CreditResponse, error) {
 	if application.Amount <= 0 {
 		return models.CreditResponse{}, ErrInvalidCreditAmount
 	}
 
-	if application.Term < 3 || application.Term > 60 {
-		return models.CreditResponse{}, ErrInvalidCreditTerm
+	product, err := s.resolveProduct(application.ProductID)
+	if err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	if err := checkProductLimits(product, application.Amount, application.Term); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	if application.DeclaredIncome < 0 {
+		return models.CreditResponse{}, ErrInvalidDeclaredIncome
+	}
+
+	if application.RepaymentType == "" {
+		application.RepaymentType = models.RepaymentTypeAnnuity // Значение по умолчанию
+	}
+
+	if !application.RepaymentType.IsValid() {
+		return models.CreditResponse{}, ErrInvalidRepaymentType
+	}
+
+	frozen, err := s.applicationsFrozen(userID)
+	if err != nil {
+		return models.CreditResponse{}, err
+	}
+	if frozen {
+		return models.CreditResponse{}, ErrCreditApplicationsFrozen
 	}
 
 	account, err := s.accountRepo.GetByID(application.AccountID)
@@ -66,38 +166,47 @@ func (s *creditService) Apply(userID int64, application models.CreditApplication
 		return models.CreditResponse{}, ErrAccountAccessDenied
 	}
 
-	// Получение ключевой ставки ЦБ РФ
-	keyRate, err := s.cbrService.GetKeyRate()
-	if err != nil {
-		keyRate = 7.5
+	if err := s.checkEligibility(product, userID, application.DeclaredIncome); err != nil {
+		return models.CreditResponse{}, err
 	}
 
-	interestRate := keyRate + 5.0
-
-	monthlyInterestRate := interestRate / 100 / 12
-	monthlyPayment := application.Amount * monthlyInterestRate * math.Pow(1+monthlyInterestRate, float64(application.Term)) /
-		(math.Pow(1+monthlyInterestRate, float64(application.Term)) - 1)
-
-	totalPayment := monthlyPayment * float64(application.Term)
-
 	now := time.Now()
-	startDate := now
-	endDate := now.AddDate(0, application.Term, 0)
-
 	credit := models.Credit{
 		UserID:         userID,
 		AccountID:      application.AccountID,
+		ProductID:      product.ID,
 		Amount:         application.Amount,
+		IssuanceFee:    product.IssuanceFee(application.Amount),
 		Term:           application.Term,
-		InterestRate:   interestRate,
-		MonthlyPayment: monthlyPayment,
-		TotalPayment:   totalPayment,
-		Status:         models.CreditStatusApproved,
-		StartDate:      startDate,
-		EndDate:        endDate,
+		RepaymentType:  application.RepaymentType,
+		DeclaredIncome: application.DeclaredIncome,
+		Status:         models.CreditStatusPending,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
+	applyProductRate(&credit, product, s.keyRate(now))
+	s.priceCredit(&credit, now)
+
+	input, err := s.scoringInput(credit)
+	if err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	result := s.scoringEngine.Evaluate(input)
+	credit.Score = result.Score
+	credit.DecisionReason = result.Reason
+	credit.RateAdjustment = result.RateAdjustment
+	s.priceCredit(&credit, now)
+
+	if result.Decision != models.ScoringDecisionRejected && s.exceedsFullCostCap(credit) {
+		result.Decision = models.ScoringDecisionRejected
+		result.Reason = fmt.Sprintf("full cost of credit %.3f%% exceeds regulatory cap %.3f%%", credit.FullCostRate, s.config.FullCostCap)
+		credit.DecisionReason = result.Reason
+	}
+
+	if result.Decision != models.ScoringDecisionReview {
+		credit.DecidedAt = &now
+	}
 
 	tx, err := s.creditRepo.BeginTx()
 	if err != nil {
@@ -112,175 +221,1301 @@ func (s *creditService) Apply(userID int64, application models.CreditApplication
 
 	credit.ID = creditID
 
-	paymentSchedules, err := s.generatePaymentSchedule(credit)
-	if err != nil {
+	if err := s.creditRepo.AddStatusHistoryTx(tx, models.CreditStatusHistory{
+		CreditID:  credit.ID,
+		ToStatus:  credit.Status,
+		Reason:    "application submitted",
+		ChangedAt: now,
+	}); err != nil {
 		return models.CreditResponse{}, err
 	}
 
-	if err := s.paymentRepo.CreateBatchTx(tx, paymentSchedules); err != nil {
-		return models.CreditResponse{}, err
-	}
+	switch result.Decision {
+	case models.ScoringDecisionApproved:
+		if err := s.transitionTx(tx, &credit, models.CreditStatusApproved, result.Reason); err != nil {
+			return models.CreditResponse{}, err
+		}
 
-	newBalance := account.Balance + application.Amount
-	if err := s.accountRepo.UpdateBalanceTx(tx, account.ID, newBalance); err != nil {
-		return models.CreditResponse{}, err
+		if err := s.disburseTx(tx, &credit, account); err != nil {
+			return models.CreditResponse{}, err
+		}
+	case models.ScoringDecisionRejected:
+		if err := s.transitionTx(tx, &credit, models.CreditStatusRejected, result.Reason); err != nil {
+			return models.CreditResponse{}, err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
 		return models.CreditResponse{}, err
 	}
 
-	go s.emailService.SendCreditApprovalEmail(
-		credit.UserID,
-		credit.Amount,
-		credit.InterestRate,
-		credit.MonthlyPayment,
-		credit.Term,
-	)
+	s.notifyDecision(credit, result.Decision)
 
 	return models.ToCreditResponse(credit), nil
 }
 
-func (s *creditService) GetByID(id int64, userID int64) (models.CreditResponse, error) {
-	credit, err := s.creditRepo.GetByID(id)
+func (s *creditService) GetStatusHistory(creditID int64, userID int64) ([]models.CreditStatusHistory, error) {
+	credit, err := s.creditRepo.GetByID(creditID)
 	if err != nil {
-		return models.CreditResponse{}, ErrCreditNotFound
+		return nil, ErrCreditNotFound
 	}
 
 	if credit.UserID != userID {
-		return models.CreditResponse{}, ErrCreditAccessDenied
+		return nil, ErrCreditAccessDenied
 	}
 
-	return models.ToCreditResponse(credit), nil
+	return s.creditRepo.GetStatusHistory(creditID)
 }
 
-func (s *creditService) GetByUserID(userID int64) ([]models.CreditResponse, error) {
-	credits, err := s.creditRepo.GetByUserID(userID)
+func (s *creditService) GetRateHistory(creditID int64, userID int64) ([]models.CreditRateHistory, error) {
+	credit, err := s.creditRepo.GetByID(creditID)
+	if err != nil {
+		return nil, ErrCreditNotFound
+	}
+
+	if credit.UserID != userID {
+		return nil, ErrCreditAccessDenied
+	}
+
+	return s.creditRepo.GetRateHistory(creditID)
+}
+
+// Quote рассчитывает условия кредита и черновой график платежей для каждой
+// комбинации срока и типа погашения, ничего не сохраняя. Ставка указывается
+// без поправки скоринга, которая определяется при подаче заявки.
+func (s *creditService) Quote(request models.CreditQuoteRequest) ([]models.CreditQuote, error) {
+	if request.Amount <= 0 {
+		return nil, ErrInvalidCreditAmount
+	}
+
+	terms := request.Terms
+	if request.Term != 0 {
+		terms = append([]int{request.Term}, terms...)
+	}
+
+	repaymentTypes := request.RepaymentTypes
+	if request.RepaymentType != "" {
+		repaymentTypes = append([]models.RepaymentType{request.RepaymentType}, repaymentTypes...)
+	}
+	if len(repaymentTypes) == 0 {
+		repaymentTypes = []models.RepaymentType{models.RepaymentTypeAnnuity}
+	}
+
+	if len(terms) == 0 || len(terms)*len(repaymentTypes) > maxQuoteVariants {
+		return nil, ErrInvalidQuoteRequest
+	}
+
+	product, err := s.resolveProduct(request.ProductID)
 	if err != nil {
 		return nil, err
 	}
 
-	var response []models.CreditResponse
-	for _, credit := range credits {
-		response = append(response, models.ToCreditResponse(credit))
+	for _, term := range terms {
+		if err := checkProductLimits(product, request.Amount, term); err != nil {
+			return nil, err
+		}
 	}
 
-	return response, nil
+	for _, repaymentType := range repaymentTypes {
+		if !repaymentType.IsValid() {
+			return nil, ErrInvalidRepaymentType
+		}
+	}
+
+	now := time.Now()
+	keyRate := s.keyRate(now)
+
+	var quotes []models.CreditQuote
+	for _, repaymentType := range repaymentTypes {
+		for _, term := range terms {
+			credit := models.Credit{
+				ProductID:     product.ID,
+				Amount:        request.Amount,
+				IssuanceFee:   product.IssuanceFee(request.Amount),
+				Term:          term,
+				RepaymentType: repaymentType,
+			}
+			applyProductRate(&credit, product, keyRate)
+			s.priceCredit(&credit, now)
+
+			schedules, err := s.generatePaymentSchedule(credit)
+			if err != nil {
+				return nil, err
+			}
+
+			quote := models.CreditQuote{
+				ProductID:      credit.ProductID,
+				Amount:         credit.Amount,
+				IssuanceFee:    credit.IssuanceFee,
+				Term:           credit.Term,
+				RepaymentType:  credit.RepaymentType,
+				InterestRate:   credit.InterestRate,
+				IsFloating:     credit.IsFloating,
+				KeyRate:        credit.KeyRate,
+				KeyRateStale:   credit.KeyRateStale,
+				MonthlyPayment: credit.MonthlyPayment,
+				TotalPayment:   credit.TotalPayment,
+				Overpayment:    credit.TotalPayment - credit.Amount,
+				FullCostRate:   credit.FullCostRate,
+				FullCostAmount: credit.FullCostAmount,
+				ExceedsCap:     s.exceedsFullCostCap(credit),
+			}
+			for _, schedule := range schedules {
+				quote.Schedule = append(quote.Schedule, models.ToPaymentScheduleResponse(schedule))
+			}
+
+			quotes = append(quotes, quote)
+		}
+	}
+
+	return quotes, nil
 }
 
-func (s *creditService) GetSchedule(creditID int64, userID int64) ([]models.PaymentScheduleResponse, error) {
-	credit, err := s.creditRepo.GetByID(creditID)
+// keyRate возвращает ключевую ставку ЦБ РФ, действующую на дату date. Кредит рассчитывается
+// по ставке на дату выдачи, поэтому расчет можно воспроизвести по истории ставки. Если
+// ставку получить не удалось, используется резервная ставка FallbackKeyRate с признаком Stale.
+func (s *creditService) keyRate(date time.Time) models.KeyRate {
+	keyRate, err := s.cbrService.GetKeyRateOnDate(context.Background(), date)
 	if err != nil {
-		return nil, ErrCreditNotFound
+		return models.KeyRate{Rate: s.config.FallbackKeyRate, Stale: true}
 	}
 
-	if credit.UserID != userID {
-		return nil, ErrCreditAccessDenied
+	return keyRate
+}
+
+// applyProductRate фиксирует в кредите составляющие базовой ставки: ключевую ставку
+// ЦБ РФ и надбавку продукта, а также признак плавающей ставки
+func applyProductRate(credit *models.Credit, product models.CreditProduct, keyRate models.KeyRate) {
+	credit.KeyRate = keyRate.Rate
+	credit.KeyRateStale = keyRate.Stale
+	credit.RateSpread = product.RateSpread
+	credit.IsFloating = product.IsFloating
+	credit.DayCount = product.DayCount
+}
+
+// resolveProduct возвращает действующий продукт из заявки или продукт по умолчанию
+func (s *creditService) resolveProduct(productID int64) (models.CreditProduct, error) {
+	var product models.CreditProduct
+	var err error
+
+	if productID == 0 {
+		product, err = s.productRepo.GetByCode(defaultCreditProductCode)
+	} else {
+		product, err = s.productRepo.GetByID(productID)
 	}
 
-	schedules, err := s.paymentRepo.GetByCreditID(creditID)
+	if err != nil || !product.IsActive {
+		return models.CreditProduct{}, ErrCreditProductNotFound
+	}
+
+	return product, nil
+}
+
+func checkProductLimits(product models.CreditProduct, amount float64, term int) error {
+	if amount < product.MinAmount || amount > product.MaxAmount {
+		return ErrCreditAmountOutOfRange
+	}
+
+	if term < product.MinTerm || term > product.MaxTerm {
+		return ErrInvalidCreditTerm
+	}
+
+	return nil
+}
+
+// checkEligibility проверяет требования продукта к доходу и числу действующих кредитов
+func (s *creditService) checkEligibility(product models.CreditProduct, userID int64, declaredIncome float64) error {
+	if product.MinIncome > 0 && declaredIncome < product.MinIncome {
+		return fmt.Errorf("%w: declared income is below %.2f", ErrCreditProductNotEligible, product.MinIncome)
+	}
+
+	if product.MaxActiveCredits > 0 {
+		credits, err := s.creditRepo.GetByUserID(userID)
+		if err != nil {
+			return err
+		}
+
+		active := 0
+		for _, credit := range credits {
+			switch credit.Status {
+			case models.CreditStatusPending, models.CreditStatusApproved, models.CreditStatusActive, models.CreditStatusOverdue:
+				active++
+			}
+		}
+
+		if active >= product.MaxActiveCredits {
+			return fmt.Errorf("%w: at most %d active credits allowed", ErrCreditProductNotEligible, product.MaxActiveCredits)
+		}
+	}
+
+	return nil
+}
+
+// GetReviewQueue возвращает заявки, ожидающие решения оператора
+func (s *creditService) GetReviewQueue() ([]models.CreditResponse, error) {
+	credits, err := s.creditRepo.GetByStatus(models.CreditStatusPending)
 	if err != nil {
 		return nil, err
 	}
 
-	var response []models.PaymentScheduleResponse
-	for _, schedule := range schedules {
-		response = append(response, models.ToPaymentScheduleResponse(schedule))
+	var response []models.CreditResponse
+	for _, credit := range credits {
+		s.fillFullCost(&credit)
+		response = append(response, models.ToCreditResponse(credit))
 	}
 
 	return response, nil
 }
 
-func (s *creditService) ProcessPendingPayments() error {
-	pendingPayments, err := s.paymentRepo.GetPendingPayments()
+// Decide фиксирует решение оператора по заявке из очереди ручной проверки.
+// При одобрении оператор может изменить предложенную скорингом поправку к ставке.
+func (s *creditService) Decide(creditID int64, request models.CreditDecisionRequest) (models.CreditResponse, error) {
+	credit, err := s.creditRepo.GetByID(creditID)
 	if err != nil {
-		return err
+		return models.CreditResponse{}, ErrCreditNotFound
 	}
 
-	for _, payment := range pendingPayments {
-		credit, err := s.creditRepo.GetByID(payment.CreditID)
-		if err != nil {
-			continue
-		}
+	if credit.Status != models.CreditStatusPending {
+		return models.CreditResponse{}, ErrCreditNotPending
+	}
+
+	now := time.Now()
+	if request.Reason != "" {
+		credit.DecisionReason = request.Reason
+	}
+
+	tx, err := s.creditRepo.BeginTx()
+	if err != nil {
+		return models.CreditResponse{}, err
+	}
+	defer tx.Rollback()
 
+	switch request.Decision {
+	case models.ScoringDecisionApproved:
 		account, err := s.accountRepo.GetByID(credit.AccountID)
 		if err != nil {
-			continue
+			return models.CreditResponse{}, ErrAccountNotFound
 		}
 
-		tx, err := s.accountRepo.BeginTx()
-		if err != nil {
-			continue
+		if request.RateAdjustment != nil {
+			credit.RateAdjustment = *request.RateAdjustment
 		}
 
-		if account.Balance >= payment.Amount {
-			newBalance := account.Balance - payment.Amount
-			if err := s.accountRepo.UpdateBalanceTx(tx, account.ID, newBalance); err != nil {
-				tx.Rollback()
-				continue
-			}
-
-			now := time.Now()
-			payment.Status = models.PaymentStatusPaid
-			payment.PaidDate = &now
-
-			if err := tx.Commit(); err != nil {
-				continue
-			}
+		// Кредит выдается сегодня и рассчитывается по ставке на дату выдачи, а не подачи заявки
+		keyRate := s.keyRate(now)
+		credit.KeyRate = keyRate.Rate
+		credit.KeyRateStale = keyRate.Stale
+		s.priceCredit(&credit, now)
+		if s.exceedsFullCostCap(credit) {
+			return models.CreditResponse{}, ErrFullCostExceedsCap
+		}
+		credit.DecidedAt = &now
 
-			s.paymentRepo.UpdateStatus(payment.ID, models.PaymentStatusPaid, &now)
+		if err := s.creditRepo.UpdateTx(tx, credit); err != nil {
+			return models.CreditResponse{}, err
+		}
 
-			go s.emailService.SendPaymentSuccessEmail(credit.UserID, payment.Amount, credit.ID)
-		} else {
-			tx.Rollback()
+		if err := s.transitionTx(tx, &credit, models.CreditStatusApproved, credit.DecisionReason); err != nil {
+			return models.CreditResponse{}, err
+		}
 
-			payment.Status = models.PaymentStatusOverdue
-			s.paymentRepo.UpdateStatus(payment.ID, models.PaymentStatusOverdue, nil)
+		if err := s.disburseTx(tx, &credit, account); err != nil {
+			return models.CreditResponse{}, err
+		}
+	case models.ScoringDecisionRejected:
+		credit.DecidedAt = &now
 
-			s.creditRepo.UpdateStatus(credit.ID, models.CreditStatusOverdue)
+		if err := s.creditRepo.UpdateTx(tx, credit); err != nil {
+			return models.CreditResponse{}, err
+		}
 
-			go s.emailService.SendPaymentOverdueEmail(credit.UserID, payment.Amount, credit.ID)
+		if err := s.transitionTx(tx, &credit, models.CreditStatusRejected, credit.DecisionReason); err != nil {
+			return models.CreditResponse{}, err
 		}
+	default:
+		return models.CreditResponse{}, ErrInvalidCreditDecision
 	}
 
-	return nil
+	if err := tx.Commit(); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	s.notifyDecision(credit, request.Decision)
+
+	return models.ToCreditResponse(credit), nil
 }
 
-func (s *creditService) generatePaymentSchedule(credit models.Credit) ([]models.PaymentSchedule, error) {
+// priceCredit рассчитывает ставку с учетом поправки скоринга, платеж, даты и ПСК кредита
+func (s *creditService) priceCredit(credit *models.Credit, now time.Time) {
+	credit.InterestRate = creditRate(*credit, credit.KeyRate)
+
+	credit.StartDate = now
+
 	var schedules []models.PaymentSchedule
+	if credit.RepaymentType == models.RepaymentTypeDifferentiated {
+		// Для дифференцированного графика указывается первый, наибольший платеж
+		schedules = s.buildDifferentiatedSchedule(*credit, credit.Amount, 1, credit.Term, credit.StartDate)
+		credit.MonthlyPayment = schedules[0].Amount
+	} else {
+		monthlyInterestRate := credit.InterestRate / 100 / 12
+		credit.MonthlyPayment = annuityPayment(credit.Amount, monthlyInterestRate, credit.Term)
+		schedules = s.buildAnnuitySchedule(*credit, credit.Amount, credit.MonthlyPayment, 1, credit.Term, credit.StartDate)
+	}
 
-	remainingDebt := credit.Amount
-	monthlyInterestRate := credit.InterestRate / 100 / 12
+	credit.EndDate = schedules[len(schedules)-1].PaymentDate
 
-	for i := 0; i < credit.Term; i++ {
-		paymentDate := credit.StartDate.AddDate(0, i+1, 0)
+	// При базе ACTUAL проценты зависят от длины месяца, и последний платеж отличается от остальных
+	credit.TotalPayment = totalScheduled(schedules)
 
-		interestPayment := remainingDebt * monthlyInterestRate
+	credit.FullCostRate, credit.FullCostAmount = fullCostOfCredit(*credit, schedules)
+}
 
-		principalPayment := credit.MonthlyPayment - interestPayment
+// creditRate возвращает ставку кредита при ключевой ставке keyRate
+func creditRate(credit models.Credit, keyRate float64) float64 {
+	return math.Max(keyRate+credit.RateSpread+credit.RateAdjustment, 0)
+}
 
-		remainingDebt -= principalPayment
+// exceedsFullCostCap проверяет ПСК по регуляторному ограничению (0 - без ограничения)
+func (s *creditService) exceedsFullCostCap(credit models.Credit) bool {
+	return s.config.FullCostCap > 0 && credit.FullCostRate > s.config.FullCostCap
+}
 
-		if i == credit.Term-1 {
-			principalPayment += remainingDebt
-			remainingDebt = 0
+// disburseTx строит график платежей, зачисляет сумму кредита на счет и переводит кредит в ACTIVE
+func (s *creditService) disburseTx(tx *sql.Tx, credit *models.Credit, account models.Account) error {
+	paymentSchedules, err := s.generatePaymentSchedule(*credit)
+	if err != nil {
+		return err
+	}
+
+	if err := s.paymentRepo.CreateBatchTx(tx, paymentSchedules); err != nil {
+		return err
+	}
+
+	if err := s.creditRepo.AddRateHistoryTx(tx, models.CreditRateHistory{
+		CreditID:       credit.ID,
+		KeyRate:        credit.KeyRate,
+		InterestRate:   credit.InterestRate,
+		MonthlyPayment: credit.MonthlyPayment,
+		EffectiveFrom:  credit.StartDate,
+		Reason:         "credit issued",
+		ChangedAt:      time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	newBalance := account.Balance + credit.Amount - credit.IssuanceFee
+	if err := s.accountRepo.UpdateBalanceTx(tx, account.ID, newBalance); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	transaction := models.Transaction{
+		UserID:          credit.UserID,
+		ToAccountID:     &account.ID,
+		Type:            models.TransactionTypeCredit,
+		Amount:          credit.Amount,
+		Description:     fmt.Sprintf("Disbursement of credit %d", credit.ID),
+		Status:          models.TransactionStatusCompleted,
+		CreditID:        &credit.ID,
+		TransactionDate: now,
+		CreatedAt:       now,
+	}
+
+	if _, err := s.transactionRepo.CreateTx(tx, transaction); err != nil {
+		return err
+	}
+
+	if credit.IssuanceFee > 0 {
+		fee := models.Transaction{
+			UserID:          credit.UserID,
+			FromAccountID:   &account.ID,
+			Type:            models.TransactionTypePayment,
+			Amount:          credit.IssuanceFee,
+			Description:     fmt.Sprintf("Issuance fee for credit %d", credit.ID),
+			Status:          models.TransactionStatusCompleted,
+			CreditID:        &credit.ID,
+			TransactionDate: now,
+			CreatedAt:       now,
 		}
 
-		now := time.Now()
-		schedule := models.PaymentSchedule{
-			CreditID:      credit.ID,
-			PaymentDate:   paymentDate,
-			Amount:        credit.MonthlyPayment,
-			Principal:     principalPayment,
-			Interest:      interestPayment,
-			RemainingDebt: remainingDebt,
-			Status:        models.PaymentStatusPending,
-			CreatedAt:     now,
-			UpdatedAt:     now,
+		if _, err := s.transactionRepo.CreateTx(tx, fee); err != nil {
+			return err
 		}
+	}
 
-		schedules = append(schedules, schedule)
+	return s.transitionTx(tx, credit, models.CreditStatusActive, "funds disbursed")
+}
+
+// transitionTx переводит кредит в новый статус, проверяя допустимость перехода,
+// и записывает изменение в историю статусов
+func (s *creditService) transitionTx(tx *sql.Tx, credit *models.Credit, next models.CreditStatus, reason string) error {
+	if !credit.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidCreditTransition, credit.Status, next)
 	}
 
-	return schedules, nil
+	if err := s.creditRepo.UpdateStatusTx(tx, credit.ID, next); err != nil {
+		return err
+	}
+
+	from := credit.Status
+	if err := s.creditRepo.AddStatusHistoryTx(tx, models.CreditStatusHistory{
+		CreditID:   credit.ID,
+		FromStatus: &from,
+		ToStatus:   next,
+		Reason:     reason,
+		ChangedAt:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	credit.Status = next
+	return nil
+}
+
+// transition выполняет transitionTx в отдельной транзакции
+func (s *creditService) transition(credit *models.Credit, next models.CreditStatus, reason string) error {
+	tx, err := s.creditRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.transitionTx(tx, credit, next, reason); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// notifyDecision отправляет письмо о решении по заявке. Решение передается явно: одобренный
+// кредит к этому моменту уже выдан и находится в статусе ACTIVE.
+func (s *creditService) notifyDecision(credit models.Credit, decision models.ScoringDecision) {
+	switch decision {
+	case models.ScoringDecisionApproved:
+		go s.emailService.SendCreditApprovalEmail(
+			credit.UserID,
+			credit.Amount,
+			credit.InterestRate,
+			credit.MonthlyPayment,
+			credit.Term,
+		)
+	case models.ScoringDecisionRejected:
+		go s.emailService.SendCreditRejectionEmail(credit.UserID, credit.Amount, credit.DecisionReason)
+	}
+}
+
+// scoringInput собирает данные для скоринга: оборот по счетам, текущую долговую
+// нагрузку и историю просрочек заемщика
+func (s *creditService) scoringInput(credit models.Credit) (models.ScoringInput, error) {
+	input := models.ScoringInput{
+		UserID:         credit.UserID,
+		Amount:         credit.Amount,
+		Term:           credit.Term,
+		InterestRate:   credit.InterestRate,
+		MonthlyPayment: credit.MonthlyPayment,
+		DeclaredIncome: credit.DeclaredIncome,
+	}
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -scoringTurnoverDays)
+	transactions, err := s.transactionRepo.GetUserTransactionsByPeriod(credit.UserID, startDate, endDate)
+	if err != nil {
+		return models.ScoringInput{}, err
+	}
+
+	var incoming float64
+	for _, transaction := range transactions {
+		if transaction.Type == models.TransactionTypeDeposit {
+			incoming += transaction.Amount
+		}
+	}
+	input.MonthlyTurnover = incoming / (scoringTurnoverDays / 30.0)
+
+	credits, err := s.creditRepo.GetByUserID(credit.UserID)
+	if err != nil {
+		return models.ScoringInput{}, err
+	}
+
+	for _, existing := range credits {
+		switch existing.Status {
+		case models.CreditStatusApproved, models.CreditStatusActive:
+			input.ExistingMonthlyPayments += existing.MonthlyPayment
+		case models.CreditStatusOverdue:
+			input.ExistingMonthlyPayments += existing.MonthlyPayment
+			input.CurrentOverdue++
+		}
+	}
+
+	if input.ExistingDebt, err = s.creditRepo.GetOutstandingDebt(credit.UserID); err != nil {
+		return models.ScoringInput{}, err
+	}
+
+	if input.OverdueHistory, err = s.creditRepo.CountOverdueEvents(credit.UserID); err != nil {
+		return models.ScoringInput{}, err
+	}
+
+	return input, nil
+}
+
+func (s *creditService) GetByID(id int64, userID int64) (models.CreditResponse, error) {
+	credit, err := s.creditRepo.GetByID(id)
+	if err != nil {
+		return models.CreditResponse{}, ErrCreditNotFound
+	}
+
+	if credit.UserID != userID {
+		return models.CreditResponse{}, ErrCreditAccessDenied
+	}
+
+	s.fillFullCost(&credit)
+
+	return models.ToCreditResponse(credit), nil
+}
+
+func (s *creditService) GetByUserID(userID int64) ([]models.CreditResponse, error) {
+	credits, err := s.creditRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.CreditResponse
+	for _, credit := range credits {
+		s.fillFullCost(&credit)
+		response = append(response, models.ToCreditResponse(credit))
+	}
+
+	return response, nil
+}
+
+func (s *creditService) GetSchedule(creditID int64, userID int64) ([]models.PaymentScheduleResponse, error) {
+	credit, err := s.creditRepo.GetByID(creditID)
+	if err != nil {
+		return nil, ErrCreditNotFound
+	}
+
+	if credit.UserID != userID {
+		return nil, ErrCreditAccessDenied
+	}
+
+	schedules, err := s.paymentRepo.GetByCreditID(creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	penalties, err := s.penaltyRepo.GetByCreditID(creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	penaltiesBySchedule := make(map[int64][]models.CreditPenaltyResponse)
+	for _, penalty := range penalties {
+		penaltiesBySchedule[penalty.PaymentScheduleID] = append(penaltiesBySchedule[penalty.PaymentScheduleID], models.ToCreditPenaltyResponse(penalty))
+	}
+
+	var response []models.PaymentScheduleResponse
+	for _, schedule := range schedules {
+		item := models.ToPaymentScheduleResponse(schedule)
+		item.Penalties = penaltiesBySchedule[schedule.ID]
+		response = append(response, item)
+	}
+
+	return response, nil
+}
+
+// ProcessPendingPayments списывает наступившие платежи по кредитам. Платеж, который не
+// удалось списать, переводится в OVERDUE только по истечении льготного периода и
+// повторно списывается при следующих запусках. Кредит без просроченных платежей
+// возвращается из OVERDUE в ACTIVE, а после последнего платежа закрывается.
+func (s *creditService) ProcessPendingPayments() error {
+	now := time.Now()
+
+	duePayments, err := s.paymentRepo.GetDuePayments(now)
+	if err != nil {
+		return err
+	}
+
+	// Если более ранний платеж по кредиту не списан, следующие не списываются вперед него
+	blockedCredits := make(map[int64]bool)
+	collectedCredits := make(map[int64]bool)
+
+	for _, payment := range duePayments {
+		credit, err := s.creditRepo.GetByID(payment.CreditID)
+		if err != nil {
+			continue
+		}
+
+		if blockedCredits[credit.ID] {
+			s.markOverdueIfGraceExpired(credit, payment, now)
+			continue
+		}
+
+		paid, err := s.collectPayment(credit, &payment)
+		if err != nil {
+			blockedCredits[credit.ID] = true
+			continue
+		}
+
+		if paid {
+			collectedCredits[credit.ID] = true
+			continue
+		}
+
+		blockedCredits[credit.ID] = true
+		s.markOverdueIfGraceExpired(credit, payment, now)
+	}
+
+	for creditID := range collectedCredits {
+		if blockedCredits[creditID] {
+			continue
+		}
+		s.syncCreditStatus(creditID)
+	}
+
+	return nil
+}
+
+// collectPayment списывает с кредитного счета неоплаченные штрафы и остаток платежа.
+// Если средств не хватает, списывается все доступное: сначала штрафы, затем часть
+// платежа. Возвращает true, если платеж погашен полностью.
+func (s *creditService) collectPayment(credit models.Credit, payment *models.PaymentSchedule) (bool, error) {
+	account, err := s.accountRepo.GetByID(credit.AccountID)
+	if err != nil {
+		return false, err
+	}
+
+	if account.Balance <= 0 {
+		return false, nil
+	}
+
+	penalties, err := s.penaltyRepo.GetUnpaidByCreditID(credit.ID)
+	if err != nil {
+		return false, err
+	}
+
+	// Штрафы и пени погашаются раньше процентов и основного долга
+	available := account.Balance
+	var paidPenalties []models.CreditPenalty
+	penaltyTotal := 0.0
+	for _, penalty := range penalties {
+		if penalty.Amount > available-penaltyTotal {
+			break
+		}
+		paidPenalties = append(paidPenalties, penalty)
+		penaltyTotal += penalty.Amount
+	}
+
+	installmentPart := 0.0
+	if len(paidPenalties) == len(penalties) {
+		installmentPart = math.Min(payment.Outstanding(), available-penaltyTotal)
+	}
+
+	debited := penaltyTotal + installmentPart
+	if debited <= 0 {
+		return false, nil
+	}
+
+	tx, err := s.accountRepo.BeginTx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	if err := s.collectPenaltiesTx(tx, paidPenalties, now); err != nil {
+		return false, err
+	}
+
+	newBalance := account.Balance - debited
+	if err := s.accountRepo.UpdateBalanceTx(tx, account.ID, newBalance); err != nil {
+		return false, err
+	}
+
+	paidAmount := payment.PaidAmount + installmentPart
+	fullyPaid := paidAmount >= payment.Amount-0.005
+
+	status := payment.Status
+	var paidDate *time.Time
+	if fullyPaid {
+		status = models.PaymentStatusPaid
+		paidDate = &now
+	}
+
+	if installmentPart > 0 {
+		if err := s.paymentRepo.RecordPaymentTx(tx, payment.ID, paidAmount, status, paidDate); err != nil {
+			return false, err
+		}
+	}
+
+	description := fmt.Sprintf("Credit %d installment payment", credit.ID)
+	if !fullyPaid {
+		description = fmt.Sprintf("Partial credit %d installment payment", credit.ID)
+	}
+
+	transaction := models.Transaction{
+		UserID:            credit.UserID,
+		FromAccountID:     &account.ID,
+		Type:              models.TransactionTypePayment,
+		Amount:            debited,
+		Description:       description,
+		Status:            models.TransactionStatusCompleted,
+		CreditID:          &credit.ID,
+		PaymentScheduleID: &payment.ID,
+		TransactionDate:   now,
+		CreatedAt:         now,
+	}
+
+	if _, err := s.transactionRepo.CreateTx(tx, transaction); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	payment.PaidAmount = paidAmount
+	payment.Status = status
+	payment.PaidDate = paidDate
+
+	if fullyPaid {
+		go s.emailService.SendPaymentSuccessEmail(credit.UserID, debited, credit.ID)
+	}
+
+	return fullyPaid, nil
+}
+
+// markOverdueIfGraceExpired переводит PENDING платеж в OVERDUE и начисляет штраф,
+// если льготный период после даты платежа истек.
+func (s *creditService) markOverdueIfGraceExpired(credit models.Credit, payment models.PaymentSchedule, now time.Time) {
+	if payment.Status != models.PaymentStatusPending {
+		return
+	}
+
+	if now.Before(payment.PaymentDate.AddDate(0, 0, s.config.GracePeriodDays)) {
+		return
+	}
+
+	s.paymentRepo.UpdateStatus(payment.ID, models.PaymentStatusOverdue, nil)
+	if credit.Status != models.CreditStatusOverdue {
+		s.transition(&credit, models.CreditStatusOverdue, fmt.Sprintf("installment %d overdue", payment.ID))
+	}
+
+	outstanding := payment.Outstanding()
+	fine := outstanding * s.config.PenaltyFineRate
+	if fine > 0 {
+		s.penaltyRepo.Create(models.CreditPenalty{
+			CreditID:          credit.ID,
+			PaymentScheduleID: payment.ID,
+			Type:              models.PenaltyTypeFine,
+			Amount:            fine,
+			AccrualDate:       now,
+			Status:            models.PenaltyStatusUnpaid,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	}
+
+	go s.emailService.SendPaymentOverdueEmail(credit.UserID, outstanding, credit.ID, fine)
+}
+
+// syncCreditStatus закрывает кредит после оплаты последнего платежа и возвращает
+// его в ACTIVE, если по нему не осталось просроченных платежей
+func (s *creditService) syncCreditStatus(creditID int64) {
+	credit, err := s.creditRepo.GetByID(creditID)
+	if err != nil {
+		return
+	}
+
+	schedules, err := s.paymentRepo.GetByCreditID(creditID)
+	if err != nil {
+		return
+	}
+
+	hasOverdue, hasUnpaid := false, false
+	for _, schedule := range schedules {
+		switch schedule.Status {
+		case models.PaymentStatusOverdue:
+			hasOverdue = true
+			hasUnpaid = true
+		case models.PaymentStatusPending:
+			hasUnpaid = true
+		}
+	}
+
+	switch {
+	case !hasUnpaid:
+		s.transition(&credit, models.CreditStatusClosed, "all installments paid")
+	case !hasOverdue && credit.Status == models.CreditStatusOverdue:
+		s.transition(&credit, models.CreditStatusActive, "overdue installments repaid")
+	}
+}
+
+// AccruePenaltyInterest начисляет пени на просроченные платежи за каждый полный день
+// с даты платежа или с последнего начисления.
+func (s *creditService) AccruePenaltyInterest() error {
+	if s.config.PenaltyDailyRate <= 0 {
+		return nil
+	}
+
+	overduePayments, err := s.paymentRepo.GetOverduePayments()
+	if err != nil {
+		return err
+	}
+
+	// Пени по кредитам с просрочкой более 30 дней начисляются в повышенном размере
+	openCases, err := s.collectionRepo.GetOpenCases()
+	if err != nil {
+		return err
+	}
+
+	buckets := make(map[int64]models.CollectionBucket)
+	for _, collectionCase := range openCases {
+		buckets[collectionCase.CreditID] = collectionCase.Bucket
+	}
+
+	accruedThrough := make(map[int64]time.Time)
+	loadedCredits := make(map[int64]bool)
+
+	for _, payment := range overduePayments {
+		if !loadedCredits[payment.CreditID] {
+			penalties, err := s.penaltyRepo.GetByCreditID(payment.CreditID)
+			if err != nil {
+				continue
+			}
+
+			for _, penalty := range penalties {
+				if penalty.Type == models.PenaltyTypeInterest && penalty.AccrualDate.After(accruedThrough[penalty.PaymentScheduleID]) {
+					accruedThrough[penalty.PaymentScheduleID] = penalty.AccrualDate
+				}
+			}
+			loadedCredits[payment.CreditID] = true
+		}
+
+		from, ok := accruedThrough[payment.ID]
+		if !ok {
+			from = payment.PaymentDate
+		}
+
+		days := int(time.Since(from).Hours() / 24)
+		if days <= 0 {
+			continue
+		}
+
+		now := time.Now()
+		s.penaltyRepo.Create(models.CreditPenalty{
+			CreditID:          payment.CreditID,
+			PaymentScheduleID: payment.ID,
+			Type:              models.PenaltyTypeInterest,
+			Amount:            payment.Outstanding() * s.penaltyDailyRate(buckets[payment.CreditID]) * float64(days),
+			AccrualDate:       from.AddDate(0, 0, days),
+			Status:            models.PenaltyStatusUnpaid,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	}
+
+	return nil
+}
+
+func (s *creditService) collectPenaltiesTx(tx *sql.Tx, penalties []models.CreditPenalty, paidDate time.Time) error {
+	for _, penalty := range penalties {
+		if err := s.penaltyRepo.MarkPaidTx(tx, penalty.ID, paidDate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EarlyRepay досрочно погашает кредит со связанного счета. При частичном погашении
+// оставшиеся PENDING платежи отменяются и график строится заново: с прежним платежом
+// и меньшим сроком (REDUCE_TERM) либо с прежним сроком и меньшим платежом (REDUCE_PAYMENT).
+func (s *creditService) EarlyRepay(creditID int64, userID int64, request models.EarlyRepaymentRequest) (models.CreditResponse, error) {
+	credit, err := s.creditRepo.GetByID(creditID)
+	if err != nil {
+		return models.CreditResponse{}, ErrCreditNotFound
+	}
+
+	if credit.UserID != userID {
+		return models.CreditResponse{}, ErrCreditAccessDenied
+	}
+
+	if credit.Status != models.CreditStatusActive && credit.Status != models.CreditStatusOverdue {
+		return models.CreditResponse{}, ErrCreditNotRepayable
+	}
+
+	if !request.Full {
+		if request.Amount <= 0 {
+			return models.CreditResponse{}, ErrInvalidAmount
+		}
+
+		if request.Mode != models.EarlyRepaymentReduceTerm && request.Mode != models.EarlyRepaymentReducePayment {
+			return models.CreditResponse{}, ErrInvalidRepaymentMode
+		}
+	}
+
+	schedules, err := s.paymentRepo.GetByCreditID(creditID)
+	if err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	var remainingPrincipal, paidTotal float64
+	var firstPending *models.PaymentSchedule
+	pendingCount := 0
+
+	for i, schedule := range schedules {
+		switch schedule.Status {
+		case models.PaymentStatusOverdue:
+			return models.CreditResponse{}, ErrCreditHasOverdue
+		case models.PaymentStatusPaid:
+			paidTotal += schedule.Amount
+		case models.PaymentStatusPending:
+			// Частично внесенная сумма гасит сначала проценты, затем основной долг
+			paidTotal += schedule.PaidAmount
+			remainingPrincipal += schedule.Principal - math.Max(schedule.PaidAmount-schedule.Interest, 0)
+			pendingCount++
+			if firstPending == nil {
+				firstPending = &schedules[i]
+			}
+		}
+	}
+
+	if firstPending == nil {
+		return models.CreditResponse{}, ErrCreditNotRepayable
+	}
+
+	now := time.Now()
+
+	// Досрочный платеж сначала гасит проценты, начисленные на дату погашения
+	owed := interestOwed(credit, schedules, now)
+	amount := request.Amount
+	interest := math.Min(amount, owed.TotalInterest)
+	fullRepayment := request.Full || amount-interest >= remainingPrincipal-0.005
+	if fullRepayment {
+		interest = owed.TotalInterest
+		amount = remainingPrincipal + interest
+	}
+	principalRepaid := amount - interest
+
+	account, err := s.accountRepo.GetByID(credit.AccountID)
+	if err != nil {
+		return models.CreditResponse{}, ErrAccountNotFound
+	}
+
+	// Кредит гасится только собственными средствами: кредитный лимит счета CREDIT
+	// для досрочного погашения не используется
+	if account.Balance < amount {
+		return models.CreditResponse{}, ErrInsufficientFunds
+	}
+
+	tx, err := s.creditRepo.BeginTx()
+	if err != nil {
+		return models.CreditResponse{}, err
+	}
+	defer tx.Rollback()
+
+	newBalance := account.Balance - amount
+	if err := s.accountRepo.UpdateBalanceTx(tx, account.ID, newBalance); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	if err := s.paymentRepo.CancelPendingTx(tx, credit.ID); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	// Досрочный платеж сохраняется в графике отдельной оплаченной строкой
+	newPrincipal := remainingPrincipal - principalRepaid
+	earlyPayment := models.PaymentSchedule{
+		CreditID:      credit.ID,
+		PaymentDate:   now,
+		Amount:        amount,
+		Principal:     principalRepaid,
+		Interest:      interest,
+		RemainingDebt: newPrincipal,
+		Status:        models.PaymentStatusPaid,
+		PaidAmount:    amount,
+		PaidDate:      &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	earlyPaymentID, err := s.paymentRepo.CreateTx(tx, earlyPayment)
+	if err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	transaction := models.Transaction{
+		UserID:            userID,
+		FromAccountID:     &account.ID,
+		Type:              models.TransactionTypePayment,
+		Amount:            amount,
+		Description:       fmt.Sprintf("Early repayment of credit %d", credit.ID),
+		Status:            models.TransactionStatusCompleted,
+		CreditID:          &credit.ID,
+		PaymentScheduleID: &earlyPaymentID,
+		TransactionDate:   now,
+		CreatedAt:         now,
+	}
+
+	if _, err := s.transactionRepo.CreateTx(tx, transaction); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	if fullRepayment {
+		credit.EndDate = now
+		credit.TotalPayment = paidTotal + amount
+	} else {
+		firstMonth := installmentMonth(credit, firstPending.PaymentDate)
+		count := pendingCount
+
+		// В льготный период первый неоплаченный платеж может быть уже просрочен: проценты
+		// по нему погашены досрочным платежом, а основной долг распределяется по платежам,
+		// которые еще не наступили, поэтому новый график начинается со следующей даты платежа
+		for !s.installmentDate(credit, firstMonth).After(now) {
+			firstMonth++
+			count--
+		}
+		if count < 1 {
+			count = 1
+		}
+
+		var newSchedules []models.PaymentSchedule
+
+		if credit.RepaymentType == models.RepaymentTypeDifferentiated {
+			// Сокращение срока сохраняет прежнюю долю основного долга в платеже
+			if request.Mode == models.EarlyRepaymentReduceTerm {
+				count = int(math.Ceil(newPrincipal/firstPending.Principal - 1e-9))
+			}
+			newSchedules = s.buildDifferentiatedSchedule(credit, newPrincipal, firstMonth, count, now)
+		} else {
+			monthlyInterestRate := credit.InterestRate / 100 / 12
+			monthlyPayment := credit.MonthlyPayment
+			if request.Mode == models.EarlyRepaymentReduceTerm {
+				count = annuityTerm(newPrincipal, monthlyInterestRate, monthlyPayment)
+			} else {
+				monthlyPayment = annuityPayment(newPrincipal, monthlyInterestRate, count)
+			}
+			newSchedules = s.buildAnnuitySchedule(credit, newPrincipal, monthlyPayment, firstMonth, count, now)
+		}
+
+		if err := s.paymentRepo.CreateBatchTx(tx, newSchedules); err != nil {
+			return models.CreditResponse{}, err
+		}
+
+		scheduledTotal := totalScheduled(newSchedules)
+
+		credit.MonthlyPayment = newSchedules[0].Amount
+		credit.Term = firstMonth - 1 + count
+		credit.EndDate = newSchedules[len(newSchedules)-1].PaymentDate
+		credit.TotalPayment = paidTotal + amount + scheduledTotal
+	}
+
+	if err := s.creditRepo.UpdateTx(tx, credit); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	if fullRepayment {
+		if err := s.transitionTx(tx, &credit, models.CreditStatusClosed, "repaid early"); err != nil {
+			return models.CreditResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.CreditResponse{}, err
+	}
+
+	go s.emailService.SendPaymentSuccessEmail(credit.UserID, amount, credit.ID)
+
+	return models.ToCreditResponse(credit), nil
+}
+
+func (s *creditService) generatePaymentSchedule(credit models.Credit) ([]models.PaymentSchedule, error) {
+	if credit.RepaymentType == models.RepaymentTypeDifferentiated {
+		return s.buildDifferentiatedSchedule(credit, credit.Amount, 1, credit.Term, credit.StartDate), nil
+	}
+	return s.buildAnnuitySchedule(credit, credit.Amount, credit.MonthlyPayment, 1, credit.Term, credit.StartDate), nil
+}
+
+// buildAnnuitySchedule строит count аннуитетных платежей на сумму principal.
+// Первый платеж приходится на firstMonth-й месяц от даты выдачи кредита, проценты
+// по нему начисляются с accruedFrom.
+func (s *creditService) buildAnnuitySchedule(credit models.Credit, principal float64, monthlyPayment float64, firstMonth int, count int, accruedFrom time.Time) []models.PaymentSchedule {
+	var schedules []models.PaymentSchedule
+
+	remainingDebt := principal
+	periodStart := accruedFrom
+
+	for i := 0; i < count; i++ {
+		paymentDate := s.installmentDate(credit, firstMonth+i)
+
+		interestPayment := periodInterest(credit, remainingDebt, periodStart, paymentDate)
+		periodStart = paymentDate
+
+		principalPayment := monthlyPayment - interestPayment
+
+		remainingDebt -= principalPayment
+
+		amount := monthlyPayment
+		if i == count-1 {
+			principalPayment += remainingDebt
+			remainingDebt = 0
+			amount = principalPayment + interestPayment
+		}
+
+		now := time.Now()
+		schedule := models.PaymentSchedule{
+			CreditID:      credit.ID,
+			PaymentDate:   paymentDate,
+			Amount:        amount,
+			Principal:     principalPayment,
+			Interest:      interestPayment,
+			RemainingDebt: remainingDebt,
+			Status:        models.PaymentStatusPending,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules
+}
+
+// buildDifferentiatedSchedule строит count платежей с равной долей основного долга
+// и процентами, начисляемыми на остаток с accruedFrom
+func (s *creditService) buildDifferentiatedSchedule(credit models.Credit, principal float64, firstMonth int, count int, accruedFrom time.Time) []models.PaymentSchedule {
+	var schedules []models.PaymentSchedule
+
+	remainingDebt := principal
+	principalPayment := principal / float64(count)
+	periodStart := accruedFrom
+
+	for i := 0; i < count; i++ {
+		paymentDate := s.installmentDate(credit, firstMonth+i)
+
+		interestPayment := periodInterest(credit, remainingDebt, periodStart, paymentDate)
+		periodStart = paymentDate
+
+		if i == count-1 {
+			principalPayment = remainingDebt
+		}
+
+		remainingDebt -= principalPayment
+
+		now := time.Now()
+		schedule := models.PaymentSchedule{
+			CreditID:      credit.ID,
+			PaymentDate:   paymentDate,
+			Amount:        principalPayment + interestPayment,
+			Principal:     principalPayment,
+			Interest:      interestPayment,
+			RemainingDebt: remainingDebt,
+			Status:        models.PaymentStatusPending,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules
+}
+
+// remainingSchedule - часть графика, которая пересчитывается начиная со следующего,
+// еще не наступившего платежа
+type remainingSchedule struct {
+	next        models.PaymentSchedule // следующий платеж
+	count       int                    // число платежей начиная со следующего
+	principal   float64                // остаток основного долга перед следующим платежом
+	firstMonth  int                    // месяц следующего платежа от даты выдачи кредита
+	accruedFrom time.Time              // дата, с которой начисляются проценты по следующему платежу
+	keptTotal   float64                // сумма платежей, которые остаются в графике без изменений
+}
+
+// splitSchedule находит следующий платеж после now. Наступившие, но не оплаченные
+// платежи остаются в графике. Возвращает false, если будущих платежей нет.
+func splitSchedule(credit models.Credit, schedules []models.PaymentSchedule, now time.Time) (remainingSchedule, bool) {
+	remaining := remainingSchedule{accruedFrom: credit.StartDate}
+	found := false
+
+	for _, schedule := range schedules {
+		if schedule.Status == models.PaymentStatusCanceled {
+			continue
+		}
+
+		if !found && schedule.Status == models.PaymentStatusPending && schedule.PaymentDate.After(now) {
+			remaining.next = schedule
+			found = true
+		}
+
+		if found && schedule.Status == models.PaymentStatusPending {
+			remaining.count++
+			continue
+		}
+
+		remaining.keptTotal += schedule.Amount
+		remaining.accruedFrom = schedule.PaymentDate
+	}
+
+	if !found {
+		return remainingSchedule{}, false
+	}
+
+	remaining.principal = remaining.next.RemainingDebt + remaining.next.Principal
+	remaining.firstMonth = installmentMonth(credit, remaining.next.PaymentDate)
+
+	return remaining, true
+}
+
+// rebuildSchedule строит count платежей на principal по текущей ставке кредита.
+// Аннуитетный платеж пересчитывается под новый остаток и срок.
+func (s *creditService) rebuildSchedule(credit models.Credit, principal float64, firstMonth int, count int, accruedFrom time.Time) []models.PaymentSchedule {
+	if credit.RepaymentType == models.RepaymentTypeDifferentiated {
+		return s.buildDifferentiatedSchedule(credit, principal, firstMonth, count, accruedFrom)
+	}
+
+	monthlyPayment := annuityPayment(principal, credit.InterestRate/100/12, count)
+	return s.buildAnnuitySchedule(credit, principal, monthlyPayment, firstMonth, count, accruedFrom)
+}
+
+func totalScheduled(schedules []models.PaymentSchedule) float64 {
+	total := 0.0
+	for _, schedule := range schedules {
+		total += schedule.Amount
+	}
+	return total
+}
+
+func annuityPayment(principal float64, monthlyInterestRate float64, months int) float64 {
+	if monthlyInterestRate == 0 {
+		return principal / float64(months)
+	}
+
+	factor := math.Pow(1+monthlyInterestRate, float64(months))
+	return principal * monthlyInterestRate * factor / (factor - 1)
+}
+
+// annuityTerm возвращает число месяцев, за которое principal гасится платежом monthlyPayment
+func annuityTerm(principal float64, monthlyInterestRate float64, monthlyPayment float64) int {
+	if monthlyInterestRate == 0 {
+		return int(math.Ceil(principal / monthlyPayment))
+	}
+
+	months := -math.Log(1-principal*monthlyInterestRate/monthlyPayment) / math.Log(1+monthlyInterestRate)
+	// Погрешность float не должна добавлять лишний месяц с копеечным платежом
+	return int(math.Ceil(months - 1e-9))
+}
+
+// installmentDate возвращает дату платежа через month месяцев после выдачи кредита:
+// то же число месяца (в коротком месяце - последний день), перенесенное на ближайший
+// рабочий день по производственному календарю
+func (s *creditService) installmentDate(credit models.Credit, month int) time.Time {
+	return s.calendar.NextBusinessDay(models.AddMonths(credit.StartDate, month))
+}
+
+// installmentMonth возвращает номер месяца от даты выдачи, к которому относится платеж.
+// Перенос на рабочий день может сдвинуть платеж на начало следующего месяца, поэтому
+// номер определяется по последней плановой дате, не превышающей дату платежа.
+func installmentMonth(credit models.Credit, paymentDate time.Time) int {
+	months, _ := models.WholeMonthsBetween(credit.StartDate, paymentDate)
+	return months
 }
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"bank-service/internal/models"
+	"bank-service/internal/repository"
+)
+
+var (
+	ErrDisputeNotFound          = errors.New("dispute not found")
+	ErrDisputeAccessDenied      = errors.New("access to this dispute is denied")
+	ErrInvalidDisputeReason     = errors.New("invalid dispute reason code")
+	ErrInvalidDisputeDecision   = errors.New("decision must be ACCEPTED or REJECTED")
+	ErrInvalidDisputeTransition = errors.New("dispute cannot be moved to this status")
+	ErrTransactionNotFound      = errors.New("transaction not found")
+	ErrTransactionNotDisputable = errors.New("only card payments can be disputed")
+	ErrDisputeWindowExpired     = errors.New("dispute window for this transaction has expired")
+	ErrDisputeAlreadyExists     = errors.New("transaction is already disputed")
+)
+
+// Срок, в течение которого клиент может оспорить операцию по карте
+const disputeWindowDays = 120
+
+type DisputeService interface {
+	Open(userID int64, request models.DisputeCreation) (models.DisputeResponse, error)
+	GetByID(id int64, userID int64) (models.DisputeResponse, error)
+	GetByUserID(userID int64) ([]models.DisputeResponse, error)
+	GetByStatus(status models.DisputeStatus) ([]models.DisputeResponse, error)
+	StartReview(id int64) (models.DisputeResponse, error)
+	Resolve(id int64, resolution models.DisputeResolution) (models.DisputeResponse, error)
+	FinalizeReversal(id int64) (models.DisputeResponse, error)
+}
+
+type disputeService struct {
+	disputeRepo     repository.DisputeRepository
+	transactionRepo repository.TransactionRepository
+	accountRepo     repository.AccountRepository
+}
+
+func NewDisputeService(
+	disputeRepo repository.DisputeRepository,
+	transactionRepo repository.TransactionRepository,
+	accountRepo repository.AccountRepository,
+) DisputeService {
+	return &disputeService{
+		disputeRepo:     disputeRepo,
+		transactionRepo: transactionRepo,
+		accountRepo:     accountRepo,
+	}
+}
+
+func (s *disputeService) Open(userID int64, request models.DisputeCreation) (models.DisputeResponse, error) {
+	if !request.ReasonCode.IsValid() {
+		return models.DisputeResponse{}, ErrInvalidDisputeReason
+	}
+
+	transaction, err := s.transactionRepo.GetByID(request.TransactionID)
+	if err != nil {
+		return models.DisputeResponse{}, ErrTransactionNotFound
+	}
+
+	if transaction.UserID != userID {
+		return models.DisputeResponse{}, ErrAccountAccessDenied
+	}
+
+	// Оспорить можно только оплату картой: платежи по кредитам и кредитным линиям
+	// тоже имеют тип PAYMENT, но не привязаны к карте
+	if transaction.Type != models.TransactionTypePayment || transaction.CardID == nil || transaction.FromAccountID == nil {
+		return models.DisputeResponse{}, ErrTransactionNotDisputable
+	}
+
+	if time.Since(transaction.TransactionDate) > disputeWindowDays*24*time.Hour {
+		return models.DisputeResponse{}, ErrDisputeWindowExpired
+	}
+
+	disputed, err := s.disputeRepo.CheckTransactionDisputed(transaction.ID)
+	if err != nil {
+		return models.DisputeResponse{}, err
+	}
+
+	if disputed {
+		return models.DisputeResponse{}, ErrDisputeAlreadyExists
+	}
+
+	now := time.Now()
+	dispute := models.CardDispute{
+		UserID:        userID,
+		TransactionID: transaction.ID,
+		AccountID:     *transaction.FromAccountID,
+		Amount:        transaction.Amount,
+		ReasonCode:    request.ReasonCode,
+		Description:   request.Description,
+		Status:        models.DisputeStatusOpened,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	id, err := s.disputeRepo.Create(dispute)
+	if err != nil {
+		return models.DisputeResponse{}, err
+	}
+
+	dispute.ID = id
+
+	return models.ToDisputeResponse(dispute), nil
+}
+
+func (s *disputeService) GetByID(id int64, userID int64) (models.DisputeResponse, error) {
+	dispute, err := s.disputeRepo.GetByID(id)
+	if err != nil {
+		return models.DisputeResponse{}, ErrDisputeNotFound
+	}
+
+	if dispute.UserID != userID {
+		return models.DisputeResponse{}, ErrDisputeAccessDenied
+	}
+
+	return models.ToDisputeResponse(dispute), nil
+}
+
+func (s *disputeService) GetByUserID(userID int64) ([]models.DisputeResponse, error) {
+	disputes, err := s.disputeRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.DisputeResponse
+	for _, dispute := range disputes {
+		response = append(response, models.ToDisputeResponse(dispute))
+	}
+
+	return response, nil
+}
+
+func (s *disputeService) GetByStatus(status models.DisputeStatus) ([]models.DisputeResponse, error) {
+	disputes, err := s.disputeRepo.GetByStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.DisputeResponse
+	for _, dispute := range disputes {
+		response = append(response, models.ToDisputeResponse(dispute))
+	}
+
+	return response, nil
+}
+
+func (s *disputeService) StartReview(id int64) (models.DisputeResponse, error) {
+	dispute, err := s.disputeRepo.GetByID(id)
+	if err != nil {
+		return models.DisputeResponse{}, ErrDisputeNotFound
+	}
+
+	if dispute.Status != models.DisputeStatusOpened {
+		return models.DisputeResponse{}, ErrInvalidDisputeTransition
+	}
+
+	updated, err := s.disputeRepo.UpdateStatus(dispute.ID, models.DisputeStatusOpened, models.DisputeStatusUnderReview)
+	if err != nil {
+		return models.DisputeResponse{}, err
+	}
+	if !updated {
+		return models.DisputeResponse{}, ErrInvalidDisputeTransition
+	}
+
+	dispute.Status = models.DisputeStatusUnderReview
+
+	return models.ToDisputeResponse(dispute), nil
+}
+
+// Resolve закрывает спор решением оператора. При положительном решении
+// клиенту сразу зачисляется предварительный возврат (PROVISIONAL), который
+// становится окончательным после FinalizeReversal.
+func (s *disputeService) Resolve(id int64, resolution models.DisputeResolution) (models.DisputeResponse, error) {
+	if resolution.Decision != models.DisputeStatusAccepted && resolution.Decision != models.DisputeStatusRejected {
+		return models.DisputeResponse{}, ErrInvalidDisputeDecision
+	}
+
+	dispute, err := s.disputeRepo.GetByID(id)
+	if err != nil {
+		return models.DisputeResponse{}, ErrDisputeNotFound
+	}
+
+	if dispute.Status != models.DisputeStatusUnderReview {
+		return models.DisputeResponse{}, ErrInvalidDisputeTransition
+	}
+
+	tx, err := s.disputeRepo.BeginTx()
+	if err != nil {
+		return models.DisputeResponse{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	if resolution.Decision == models.DisputeStatusAccepted {
+		transaction := models.Transaction{
+			UserID:          dispute.UserID,
+			ToAccountID:     &dispute.AccountID,
+			Type:            models.TransactionTypeChargeback,
+			Amount:          dispute.Amount,
+			Description:     fmt.Sprintf("Provisional credit for dispute %d", dispute.ID),
+			Status:          models.TransactionStatusProvisional,
+			TransactionDate: now,
+			CreatedAt:       now,
+		}
+
+		transactionID, err := s.transactionRepo.CreateTx(tx, transaction)
+		if err != nil {
+			return models.DisputeResponse{}, err
+		}
+
+		dispute.ProvisionalCreditTxID = &transactionID
+	}
+
+	dispute.Status = resolution.Decision
+	dispute.ResolutionComment = resolution.Comment
+	dispute.ResolvedAt = &now
+
+	// Решение сохраняется, только если спор все еще на рассмотрении: параллельное решение
+	// откатывает транзакцию, и возврат не зачисляется дважды
+	resolved, err := s.disputeRepo.ResolveTx(tx, dispute)
+	if err != nil {
+		return models.DisputeResponse{}, err
+	}
+	if !resolved {
+		return models.DisputeResponse{}, ErrInvalidDisputeTransition
+	}
+
+	if resolution.Decision == models.DisputeStatusAccepted {
+		if err := s.accountRepo.AddToBalanceTx(tx, dispute.AccountID, dispute.Amount); err != nil {
+			return models.DisputeResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.DisputeResponse{}, err
+	}
+
+	return models.ToDisputeResponse(dispute), nil
+}
+
+func (s *disputeService) FinalizeReversal(id int64) (models.DisputeResponse, error) {
+	dispute, err := s.disputeRepo.GetByID(id)
+	if err != nil {
+		return models.DisputeResponse{}, ErrDisputeNotFound
+	}
+
+	if dispute.Status != models.DisputeStatusAccepted || dispute.ProvisionalCreditTxID == nil || dispute.FinalReversalAt != nil {
+		return models.DisputeResponse{}, ErrInvalidDisputeTransition
+	}
+
+	tx, err := s.disputeRepo.BeginTx()
+	if err != nil {
+		return models.DisputeResponse{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	finalized, err := s.disputeRepo.FinalizeReversalTx(tx, dispute.ID, now)
+	if err != nil {
+		return models.DisputeResponse{}, err
+	}
+	if !finalized {
+		return models.DisputeResponse{}, ErrInvalidDisputeTransition
+	}
+
+	if err := s.transactionRepo.UpdateStatusTx(tx, *dispute.ProvisionalCreditTxID, models.TransactionStatusCompleted); err != nil {
+		return models.DisputeResponse{}, err
+	}
+
+	dispute.FinalReversalAt = &now
+
+	if err := tx.Commit(); err != nil {
+		return models.DisputeResponse{}, err
+	}
+
+	return models.ToDisputeResponse(dispute), nil
+}
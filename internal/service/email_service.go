@@ -11,8 +11,15 @@ import (
 
 type EmailService interface {
 	SendCreditApprovalEmail(userID int64, amount float64, interestRate float64, monthlyPayment float64, term int) error
+	SendCreditRejectionEmail(userID int64, amount float64, reason string) error
 	SendPaymentSuccessEmail(userID int64, amount float64, creditID int64) error
-	SendPaymentOverdueEmail(userID int64, amount float64, creditID int64) error
+	SendPaymentOverdueEmail(userID int64, amount float64, creditID int64, fine float64) error
+	SendCreditRateChangeEmail(userID int64, creditID int64, interestRate float64, monthlyPayment float64, effectiveFrom time.Time) error
+	SendCreditRestructuringEmail(userID int64, creditID int64, description string, monthlyPayment float64, firstPaymentDate time.Time, endDate time.Time) error
+	SendCollectionNoticeEmail(userID int64, creditID int64, daysPastDue int, overdueAmount float64, notice string) error
+	SendCreditLineStatementEmail(userID int64, accountNumber string, debt float64, minimumPayment float64, dueDate time.Time) error
+	SendCreditLineOverdueEmail(userID int64, accountNumber string, minimumPayment float64, lateFee float64) error
+	SendOperationCodeEmail(userID int64, code string, operationType string, amount float64, expiresAt time.Time) error
 }
 
 type emailService struct {
@@ -45,6 +52,24 @@ func (s *emailService) SendCreditApprovalEmail(userID int64, amount float64, int
 	return s.sendEmail(userEmail, subject, body)
 }
 
+func (s *emailService) SendCreditRejectionEmail(userID int64, amount float64, reason string) error {
+	subject := "Решение по кредитной заявке"
+	body := fmt.Sprintf(`
+		<h1>К сожалению, ваша заявка на кредит отклонена</h1>
+		<p>Детали заявки:</p>
+		<ul>
+			<li>Сумма: %.2f руб.</li>
+			<li>Причина: %s</li>
+		</ul>
+		<p>Вы можете подать новую заявку после изменения условий.</p>
+		<p>С уважением, Ваш Банк</p>
+	`, amount, reason)
+
+	userEmail := "user@example.com"
+
+	return s.sendEmail(userEmail, subject, body)
+}
+
 func (s *emailService) SendPaymentSuccessEmail(userID int64, amount float64, creditID int64) error {
 	subject := "Платеж по кредиту выполнен успешно"
 	body := fmt.Sprintf(`
@@ -64,7 +89,7 @@ func (s *emailService) SendPaymentSuccessEmail(userID int64, amount float64, cre
 	return s.sendEmail(userEmail, subject, body)
 }
 
-func (s *emailService) SendPaymentOverdueEmail(userID int64, amount float64, creditID int64) error {
+func (s *emailService) SendPaymentOverdueEmail(userID int64, amount float64, creditID int64, fine float64) error {
 	subject := "Важно: Просрочка платежа по кредиту"
 	body := fmt.Sprintf(`
 		<h1>Уведомление о просрочке платежа</h1>
@@ -75,10 +100,121 @@ func (s *emailService) SendPaymentOverdueEmail(userID int64, amount float64, cre
 			<li>Сумма платежа: %.2f руб.</li>
 			<li>Дата платежа: %s</li>
 		</ul>
-		<p>На сумму просроченного платежа будет начислен штраф в размере 10%%.</p>
+		<p>Начислен штраф за просрочку: %.2f руб. Штрафы и пени списываются в первую очередь при следующем платеже.</p>
 		<p>Пожалуйста, пополните счет для погашения задолженности.</p>
 		<p>С уважением, Ваш Банк</p>
-	`, creditID, amount, time.Now().Format("02.01.2006"))
+	`, creditID, amount, time.Now().Format("02.01.2006"), fine)
+
+	userEmail := "user@example.com"
+
+	return s.sendEmail(userEmail, subject, body)
+}
+
+func (s *emailService) SendCreditRateChangeEmail(userID int64, creditID int64, interestRate float64, monthlyPayment float64, effectiveFrom time.Time) error {
+	subject := "Изменение ставки по кредиту"
+	body := fmt.Sprintf(`
+		<h1>Изменение процентной ставки</h1>
+		<p>Уважаемый клиент,</p>
+		<p>В связи с изменением ключевой ставки Банка России пересмотрена ставка по кредиту №%d.</p>
+		<ul>
+			<li>Новая процентная ставка: %.2f%%</li>
+			<li>Новый ежемесячный платеж: %.2f руб.</li>
+			<li>Действует с платежа: %s</li>
+		</ul>
+		<p>Обновленный график платежей доступен в личном кабинете.</p>
+		<p>С уважением, Ваш Банк</p>
+	`, creditID, interestRate, monthlyPayment, effectiveFrom.Format("02.01.2006"))
+
+	userEmail := "user@example.com"
+
+	return s.sendEmail(userEmail, subject, body)
+}
+
+func (s *emailService) SendCreditRestructuringEmail(userID int64, creditID int64, description string, monthlyPayment float64, firstPaymentDate time.Time, endDate time.Time) error {
+	subject := "Изменение графика платежей по кредиту"
+	body := fmt.Sprintf(`
+		<h1>График платежей изменен</h1>
+		<p>Уважаемый клиент,</p>
+		<p>По вашему заявлению изменены условия кредита №%d: %s.</p>
+		<ul>
+			<li>Новый ежемесячный платеж: %.2f руб.</li>
+			<li>Первый платеж по новому графику: %s</li>
+			<li>Дата окончания кредита: %s</li>
+		</ul>
+		<p>Обновленный график платежей доступен в личном кабинете.</p>
+		<p>С уважением, Ваш Банк</p>
+	`, creditID, description, monthlyPayment, firstPaymentDate.Format("02.01.2006"), endDate.Format("02.01.2006"))
+
+	userEmail := "user@example.com"
+
+	return s.sendEmail(userEmail, subject, body)
+}
+
+func (s *emailService) SendCollectionNoticeEmail(userID int64, creditID int64, daysPastDue int, overdueAmount float64, notice string) error {
+	subject := "Просроченная задолженность по кредиту"
+	body := fmt.Sprintf(`
+		<h1>Задолженность по кредиту не погашена</h1>
+		<p>Уважаемый клиент,</p>
+		<p>По кредиту №%d просрочка составляет %d дн., просроченная сумма - %.2f руб.</p>
+		<p>%s</p>
+		<p>Пожалуйста, пополните счет кредита, чтобы погасить задолженность.</p>
+		<p>С уважением, Ваш Банк</p>
+	`, creditID, daysPastDue, overdueAmount, notice)
+
+	userEmail := "user@example.com"
+
+	return s.sendEmail(userEmail, subject, body)
+}
+
+func (s *emailService) SendCreditLineStatementEmail(userID int64, accountNumber string, debt float64, minimumPayment float64, dueDate time.Time) error {
+	subject := "Выписка по кредитной линии"
+	body := fmt.Sprintf(`
+		<h1>Сформирована выписка по кредитной линии</h1>
+		<p>Уважаемый клиент,</p>
+		<p>По счету %s сформирована ежемесячная выписка.</p>
+		<ul>
+			<li>Задолженность: %.2f руб.</li>
+			<li>Минимальный платеж: %.2f руб.</li>
+			<li>Оплатить до: %s</li>
+		</ul>
+		<p>При погашении всей задолженности до указанной даты проценты за следующий период не начисляются.</p>
+		<p>С уважением, Ваш Банк</p>
+	`, accountNumber, debt, minimumPayment, dueDate.Format("02.01.2006"))
+
+	userEmail := "user@example.com"
+
+	return s.sendEmail(userEmail, subject, body)
+}
+
+func (s *emailService) SendCreditLineOverdueEmail(userID int64, accountNumber string, minimumPayment float64, lateFee float64) error {
+	subject := "Пропущен минимальный платеж по кредитной линии"
+	body := fmt.Sprintf(`
+		<h1>Минимальный платеж не внесен</h1>
+		<p>Уважаемый клиент,</p>
+		<p>По счету %s не внесен минимальный платеж в размере %.2f руб.</p>
+		<p>Начислен штраф: %.2f руб. Кредитный лимит приостановлен до внесения минимального платежа.</p>
+		<p>С уважением, Ваш Банк</p>
+	`, accountNumber, minimumPayment, lateFee)
+
+	userEmail := "user@example.com"
+
+	return s.sendEmail(userEmail, subject, body)
+}
+
+func (s *emailService) SendOperationCodeEmail(userID int64, code string, operationType string, amount float64, expiresAt time.Time) error {
+	subject := "Код подтверждения операции"
+	body := fmt.Sprintf(`
+		<h1>Подтверждение операции</h1>
+		<p>Для подтверждения операции используйте код:</p>
+		<h2>%s</h2>
+		<ul>
+			<li>Тип операции: %s</li>
+			<li>Сумма: %.2f руб.</li>
+			<li>Код действителен до: %s</li>
+		</ul>
+		<p>Никому не сообщайте этот код. Если вы не совершали операцию, обратитесь в банк.</p>
+		<p>С уважением, Ваш Банк</p>
+	`, code, operationType, amount, expiresAt.Format("02.01.2006 15:04"))
 
 	userEmail := "user@example.com"
 
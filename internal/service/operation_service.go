@@ -0,0 +1,192 @@
+package service
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"bank-service/internal/config"
+	"bank-service/internal/models"
+	"bank-service/internal/repository"
+)
+
+var (
+	ErrOperationNotFound     = errors.New("operation not found")
+	ErrOperationAccessDenied = errors.New("access to this operation is denied")
+	ErrOperationNotPending   = errors.New("operation is not awaiting confirmation")
+	ErrOperationExpired      = errors.New("confirmation code has expired")
+	ErrOperationLocked       = errors.New("too many invalid confirmation attempts")
+	ErrInvalidOperationCode  = errors.New("invalid confirmation code")
+)
+
+// ConfirmationRequiredError возвращается, когда операция превысила порог
+// и отложена до подтверждения одноразовым кодом.
+type ConfirmationRequiredError struct {
+	Operation models.PendingOperationResponse
+}
+
+func (e *ConfirmationRequiredError) Error() string {
+	return fmt.Sprintf("operation %d requires confirmation", e.Operation.ID)
+}
+
+// OperationExecutor выполняет подтвержденную операцию
+type OperationExecutor func(operation models.PendingOperation) error
+
+type OperationService interface {
+	RequiresConfirmation(amount float64) bool
+	Request(userID int64, operationType models.OperationType, amount float64, payload interface{}) error
+	Confirm(id int64, userID int64, code string) (models.PendingOperationResponse, error)
+	RegisterExecutor(operationType models.OperationType, executor OperationExecutor)
+}
+
+type operationService struct {
+	operationRepo repository.OperationRepository
+	encryption    EncryptionService
+	emailService  EmailService
+	config        config.OTPConfig
+	executors     map[models.OperationType]OperationExecutor
+}
+
+func NewOperationService(
+	operationRepo repository.OperationRepository,
+	encryption EncryptionService,
+	emailService EmailService,
+	config config.OTPConfig,
+) OperationService {
+	return &operationService{
+		operationRepo: operationRepo,
+		encryption:    encryption,
+		emailService:  emailService,
+		config:        config,
+		executors:     make(map[models.OperationType]OperationExecutor),
+	}
+}
+
+func (s *operationService) RequiresConfirmation(amount float64) bool {
+	return s.config.Threshold > 0 && amount >= s.config.Threshold
+}
+
+func (s *operationService) RegisterExecutor(operationType models.OperationType, executor OperationExecutor) {
+	s.executors[operationType] = executor
+}
+
+// Request сохраняет операцию, отправляет код и всегда возвращает ошибку:
+// либо *ConfirmationRequiredError, либо причину, по которой операцию не удалось отложить.
+func (s *operationService) Request(userID int64, operationType models.OperationType, amount float64, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	code, err := generateOperationCode()
+	if err != nil {
+		return err
+	}
+
+	codeHash, err := s.encryption.CreateHMAC(code)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	operation := models.PendingOperation{
+		UserID:    userID,
+		Type:      operationType,
+		Amount:    amount,
+		Payload:   data,
+		CodeHash:  codeHash,
+		Status:    models.OperationStatusPending,
+		ExpiresAt: now.Add(s.config.TTL),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	id, err := s.operationRepo.Create(operation)
+	if err != nil {
+		return err
+	}
+
+	operation.ID = id
+
+	go s.emailService.SendOperationCodeEmail(userID, code, string(operationType), amount, operation.ExpiresAt)
+
+	return &ConfirmationRequiredError{Operation: models.ToPendingOperationResponse(operation, s.config.MaxAttempts)}
+}
+
+func (s *operationService) Confirm(id int64, userID int64, code string) (models.PendingOperationResponse, error) {
+	operation, err := s.operationRepo.GetByID(id)
+	if err != nil {
+		return models.PendingOperationResponse{}, ErrOperationNotFound
+	}
+
+	if operation.UserID != userID {
+		return models.PendingOperationResponse{}, ErrOperationAccessDenied
+	}
+
+	if operation.Status != models.OperationStatusPending {
+		return models.PendingOperationResponse{}, ErrOperationNotPending
+	}
+
+	if time.Now().After(operation.ExpiresAt) {
+		if _, err := s.operationRepo.UpdateStatus(operation.ID, models.OperationStatusPending, models.OperationStatusExpired); err != nil {
+			return models.PendingOperationResponse{}, err
+		}
+		return models.PendingOperationResponse{}, ErrOperationExpired
+	}
+
+	if err := s.encryption.VerifyHMAC(code, operation.CodeHash); err != nil {
+		_, status, err := s.operationRepo.IncrementAttempts(operation.ID, s.config.MaxAttempts)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.PendingOperationResponse{}, ErrOperationNotPending
+			}
+			return models.PendingOperationResponse{}, err
+		}
+
+		if status == models.OperationStatusLocked {
+			return models.PendingOperationResponse{}, ErrOperationLocked
+		}
+		return models.PendingOperationResponse{}, ErrInvalidOperationCode
+	}
+
+	executor, ok := s.executors[operation.Type]
+	if !ok {
+		return models.PendingOperationResponse{}, fmt.Errorf("no executor registered for operation type %s", operation.Type)
+	}
+
+	// Статус фиксируется до выполнения и только для ожидающей операции, чтобы один и тот же
+	// код нельзя было использовать повторно, в том числе в параллельных запросах
+	now := time.Now()
+	confirmed, err := s.operationRepo.MarkConfirmed(operation.ID, now)
+	if err != nil {
+		return models.PendingOperationResponse{}, err
+	}
+	if !confirmed {
+		return models.PendingOperationResponse{}, ErrOperationNotPending
+	}
+
+	operation.Status = models.OperationStatusConfirmed
+	operation.ConfirmedAt = &now
+
+	if err := executor(operation); err != nil {
+		if _, updateErr := s.operationRepo.UpdateStatus(operation.ID, models.OperationStatusConfirmed, models.OperationStatusFailed); updateErr != nil {
+			return models.PendingOperationResponse{}, fmt.Errorf("%w (failed to mark operation as failed: %v)", err, updateErr)
+		}
+		return models.PendingOperationResponse{}, err
+	}
+
+	return models.ToPendingOperationResponse(operation, s.config.MaxAttempts), nil
+}
+
+func generateOperationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"math"
+
+	"bank-service/internal/config"
+	"bank-service/internal/models"
+)
+
+// ScoringEngine принимает решение по кредитной заявке. Реализацию можно заменить,
+// передав другую в NewCreditService.
+type ScoringEngine interface {
+	Evaluate(input models.ScoringInput) models.ScoringResult
+}
+
+type ruleScoringEngine struct {
+	config config.ScoringConfig
+}
+
+func NewRuleScoringEngine(config config.ScoringConfig) ScoringEngine {
+	return &ruleScoringEngine{
+		config: config,
+	}
+}
+
+// Evaluate рассчитывает балл от 0 до 100 по долговой нагрузке, истории просрочек и
+// подтвержденности дохода оборотом по счетам. Жесткие ограничения отклоняют заявку
+// независимо от балла.
+func (e *ruleScoringEngine) Evaluate(input models.ScoringInput) models.ScoringResult {
+	income := input.DeclaredIncome
+	if income <= 0 {
+		income = input.MonthlyTurnover
+	}
+
+	if income <= 0 {
+		return rejected(0, "no declared income and no incoming turnover")
+	}
+
+	if input.CurrentOverdue > 0 {
+		return rejected(0, "existing credits have overdue installments")
+	}
+
+	if input.OverdueHistory > e.config.MaxOverdueHistory {
+		return rejected(0, fmt.Sprintf("%d overdue installments in credit history", input.OverdueHistory))
+	}
+
+	debtToIncome := (input.ExistingMonthlyPayments + input.MonthlyPayment) / income
+	if debtToIncome > e.config.MaxDebtToIncome {
+		return rejected(0, fmt.Sprintf("debt-to-income ratio %.2f exceeds %.2f", debtToIncome, e.config.MaxDebtToIncome))
+	}
+
+	score := 100.0
+	score -= debtToIncome * 100
+	score -= float64(input.OverdueHistory) * 10
+
+	// Заявленный доход, не подтвержденный поступлениями на счета, снижает балл
+	if input.DeclaredIncome > 0 && input.DeclaredIncome > input.MonthlyTurnover*2 {
+		score -= 15
+	}
+
+	// Непогашенный долг больше годового дохода
+	if input.ExistingDebt > income*12 {
+		score -= 10
+	}
+
+	result := models.ScoringResult{
+		Score: int(math.Max(math.Round(score), 0)),
+	}
+
+	switch {
+	case result.Score >= 90:
+		result.Decision = models.ScoringDecisionApproved
+		result.Reason = "excellent credit profile"
+		result.RateAdjustment = -1.0
+	case result.Score >= e.config.ApproveScore:
+		result.Decision = models.ScoringDecisionApproved
+		result.Reason = "score above approval threshold"
+	case result.Score >= e.config.ReviewScore:
+		result.Decision = models.ScoringDecisionReview
+		result.Reason = fmt.Sprintf("borderline score %d, debt-to-income ratio %.2f", result.Score, debtToIncome)
+		result.RateAdjustment = 2.0
+		if !e.config.ManualReview {
+			result.Decision = models.ScoringDecisionApproved
+		}
+	default:
+		result.Decision = models.ScoringDecisionRejected
+		result.Reason = fmt.Sprintf("score %d below threshold %d", result.Score, e.config.ReviewScore)
+	}
+
+	return result
+}
+
+func rejected(score int, reason string) models.ScoringResult {
+	return models.ScoringResult{
+		Decision: models.ScoringDecisionRejected,
+		Score:    score,
+		Reason:   reason,
+	}
+}
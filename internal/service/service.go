@@ -1,20 +1,26 @@
 package service
 
 import (
+	"bank-service/internal/calendar"
 	"bank-service/internal/config"
+	"bank-service/internal/models"
 	"bank-service/internal/repository"
 )
 
 type Services struct {
-	User        UserService
-	Account     AccountService
-	Card        CardService
-	Transaction TransactionService
-	Credit      CreditService
-	Analytics   AnalyticsService
-	CBR         CBRService
-	Email       EmailService
-	Encryption  EncryptionService
+	User          UserService
+	Account       AccountService
+	Card          CardService
+	Transaction   TransactionService
+	Credit        CreditService
+	CreditProduct CreditProductService
+	CreditLine    CreditLineService
+	Analytics     AnalyticsService
+	Dispute       DisputeService
+	Operation     OperationService
+	CBR           CBRService
+	Email         EmailService
+	Encryption    EncryptionService
 }
 
 type Dependencies struct {
@@ -22,26 +28,39 @@ type Dependencies struct {
 	EncryptionService EncryptionService
 	EmailService      EmailService
 	CBRService        CBRService
+	Calendar          *calendar.Calendar
 	Config            *config.Config
 }
 
 func NewServices(deps Dependencies) *Services {
 	userService := NewUserService(deps.Repos.User, deps.EncryptionService)
-	accountService := NewAccountService(deps.Repos.Account, deps.Repos.Transaction)
-	cardService := NewCardService(deps.Repos.Card, deps.Repos.Account, deps.EncryptionService)
+	operationService := NewOperationService(deps.Repos.Operation, deps.EncryptionService, deps.EmailService, deps.Config.OTP)
+	accountService := NewAccountService(deps.Repos.Account, deps.Repos.Transaction, operationService)
+	cardService := NewCardService(deps.Repos.Card, deps.Repos.Account, deps.Repos.Transaction, deps.Repos.CardHold, deps.EncryptionService, operationService, deps.Config.ISO8583)
 	transactionService := NewTransactionService(deps.Repos.Transaction, deps.Repos.Account)
-	creditService := NewCreditService(deps.Repos.Credit, deps.Repos.Payment, deps.Repos.Account, deps.CBRService, deps.EmailService)
-	analyticsService := NewAnalyticsService(deps.Repos.Transaction, deps.Repos.Credit, deps.Repos.Payment)
+	creditProductService := NewCreditProductService(deps.Repos.Product)
+	creditService := NewCreditService(deps.Repos.Credit, deps.Repos.Payment, deps.Repos.Account, deps.Repos.Transaction, deps.Repos.Penalty, deps.Repos.Product, deps.Repos.Collection, deps.Repos.Card, deps.CBRService, deps.EmailService, NewRuleScoringEngine(deps.Config.Scoring), deps.Calendar, deps.Config.Credit)
+	creditLineService := NewCreditLineService(deps.Repos.CreditLine, deps.Repos.Account, deps.Repos.Transaction, deps.Repos.CardHold, deps.EmailService, deps.Config.CreditLine)
+	analyticsService := NewAnalyticsService(deps.Repos.Transaction, deps.Repos.Credit, deps.Repos.Payment, deps.Repos.User)
+	disputeService := NewDisputeService(deps.Repos.Dispute, deps.Repos.Transaction, deps.Repos.Account)
+
+	operationService.RegisterExecutor(models.OperationTypeTransfer, accountService.ExecuteConfirmed)
+	operationService.RegisterExecutor(models.OperationTypeWithdraw, accountService.ExecuteConfirmed)
+	operationService.RegisterExecutor(models.OperationTypeCardPayment, cardService.ExecuteConfirmed)
 
 	return &Services{
-		User:        userService,
-		Account:     accountService,
-		Card:        cardService,
-		Transaction: transactionService,
-		Credit:      creditService,
-		Analytics:   analyticsService,
-		CBR:         deps.CBRService,
-		Email:       deps.EmailService,
-		Encryption:  deps.EncryptionService,
+		User:          userService,
+		Account:       accountService,
+		Card:          cardService,
+		Transaction:   transactionService,
+		Credit:        creditService,
+		CreditProduct: creditProductService,
+		CreditLine:    creditLineService,
+		Analytics:     analyticsService,
+		Dispute:       disputeService,
+		Operation:     operationService,
+		CBR:           deps.CBRService,
+		Email:         deps.EmailService,
+		Encryption:    deps.EncryptionService,
 	}
 }
@@ -21,6 +21,8 @@ type UserService interface {
 	Login(input models.UserLogin) (string, error)
 	GetByID(id int64) (models.UserResponse, error)
 	ValidateToken(tokenString string) (int64, error)
+	IsOperator(id int64) (bool, error)
+	UpdateDeclaredIncome(id int64, declaredIncome float64) (models.UserResponse, error)
 }
 
 type userService struct {
@@ -69,6 +71,7 @@ func (s *userService) Register(input models.UserRegistration) (models.UserRespon
 		Email:        input.Email,
 		PasswordHash: passwordHash,
 		FullName:     input.FullName,
+		Role:         models.UserRoleCustomer,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -116,6 +119,20 @@ func (s *userService) GetByID(id int64) (models.UserResponse, error) {
 	return models.ToUserResponse(user), nil
 }
 
+// UpdateDeclaredIncome сохраняет ежемесячный доход из профиля. Он используется для оценки
+// долговой нагрузки, если доход не подтверждается регулярными поступлениями.
+func (s *userService) UpdateDeclaredIncome(id int64, declaredIncome float64) (models.UserResponse, error) {
+	if declaredIncome < 0 {
+		return models.UserResponse{}, ErrInvalidDeclaredIncome
+	}
+
+	if err := s.repo.UpdateDeclaredIncome(id, declaredIncome); err != nil {
+		return models.UserResponse{}, err
+	}
+
+	return s.GetByID(id)
+}
+
 func (s *userService) ValidateToken(tokenString string) (int64, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -138,3 +155,12 @@ func (s *userService) ValidateToken(tokenString string) (int64, error) {
 
 	return 0, errors.New("invalid token")
 }
+
+func (s *userService) IsOperator(id int64) (bool, error) {
+	user, err := s.repo.GetByID(id)
+	if err != nil {
+		return false, ErrUserNotFound
+	}
+
+	return user.Role == models.UserRoleOperator, nil
+}
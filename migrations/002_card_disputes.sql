@@ -0,0 +1,29 @@
+-- Роли пользователей (операторы разбирают споры по картам)
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'CUSTOMER' CHECK (role IN ('CUSTOMER', 'OPERATOR'));
+
+-- Новый тип транзакции для возврата средств по спору
+ALTER TABLE transactions DROP CONSTRAINT transactions_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_type_check
+    CHECK (type IN ('DEPOSIT', 'WITHDRAW', 'TRANSFER', 'PAYMENT', 'CREDIT', 'CHARGEBACK'));
+
+-- Таблица споров по карточным операциям
+CREATE TABLE card_disputes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id),
+    account_id INTEGER NOT NULL REFERENCES accounts(id),
+    amount NUMERIC(15, 2) NOT NULL,
+    reason_code VARCHAR(30) NOT NULL CHECK (reason_code IN ('FRAUD', 'DUPLICATE', 'NOT_RECEIVED', 'NOT_AS_DESCRIBED', 'INCORRECT_AMOUNT', 'CANCELED')),
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('OPENED', 'UNDER_REVIEW', 'ACCEPTED', 'REJECTED')),
+    resolution_comment TEXT NOT NULL DEFAULT '',
+    provisional_credit_tx_id INTEGER REFERENCES transactions(id),
+    final_reversal_at TIMESTAMP,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_card_disputes_user_id ON card_disputes(user_id);
+CREATE INDEX idx_card_disputes_transaction_id ON card_disputes(transaction_id);
+CREATE INDEX idx_card_disputes_status ON card_disputes(status);
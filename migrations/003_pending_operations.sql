@@ -0,0 +1,18 @@
+-- Операции, ожидающие подтверждения одноразовым кодом
+CREATE TABLE pending_operations (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('TRANSFER', 'WITHDRAW', 'CARD_PAYMENT')),
+    amount NUMERIC(15, 2) NOT NULL,
+    payload JSONB NOT NULL,
+    code_hash TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'CONFIRMED', 'FAILED', 'LOCKED', 'EXPIRED')),
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pending_operations_user_id ON pending_operations(user_id);
+CREATE INDEX idx_pending_operations_status ON pending_operations(status);
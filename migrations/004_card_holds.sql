@@ -0,0 +1,20 @@
+-- Авторизации (холды) карточных операций, поступающих через ISO 8583
+CREATE TABLE card_holds (
+    id SERIAL PRIMARY KEY,
+    card_id INTEGER NOT NULL REFERENCES cards(id),
+    account_id INTEGER NOT NULL REFERENCES accounts(id),
+    amount NUMERIC(15, 2) NOT NULL,
+    rrn VARCHAR(12) NOT NULL,
+    auth_code VARCHAR(6) NOT NULL,
+    terminal_id VARCHAR(8) NOT NULL DEFAULT '',
+    merchant_name VARCHAR(40) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('ACTIVE', 'CAPTURED', 'RELEASED', 'REVERSED')),
+    transaction_id INTEGER REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (card_id, rrn)
+);
+
+CREATE INDEX idx_card_holds_card_id ON card_holds(card_id);
+CREATE INDEX idx_card_holds_status ON card_holds(status);
+CREATE INDEX idx_cards_number_hmac ON cards(number_hmac);
@@ -0,0 +1,17 @@
+-- Штрафы и пени по просроченным платежам
+CREATE TABLE credit_penalties (
+    id SERIAL PRIMARY KEY,
+    credit_id INTEGER NOT NULL REFERENCES credits(id),
+    payment_schedule_id INTEGER NOT NULL REFERENCES payment_schedules(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('FINE', 'INTEREST')),
+    amount NUMERIC(15, 2) NOT NULL,
+    accrual_date TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('UNPAID', 'PAID')),
+    paid_date TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_credit_penalties_credit_id ON credit_penalties(credit_id);
+CREATE INDEX idx_credit_penalties_payment_schedule_id ON credit_penalties(payment_schedule_id);
+CREATE INDEX idx_credit_penalties_status ON credit_penalties(status);
@@ -0,0 +1,4 @@
+-- Частичное погашение платежей по кредиту
+ALTER TABLE payment_schedules ADD COLUMN paid_amount NUMERIC(15, 2) NOT NULL DEFAULT 0;
+
+UPDATE payment_schedules SET paid_amount = amount WHERE status = 'PAID';
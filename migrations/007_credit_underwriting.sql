@@ -0,0 +1,7 @@
+-- Скоринг и решение по кредитной заявке
+ALTER TABLE credits
+    ADD COLUMN declared_income NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN score INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN decision_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN rate_adjustment NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN decided_at TIMESTAMP;
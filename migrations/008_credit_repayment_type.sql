@@ -0,0 +1,3 @@
+-- Тип погашения кредита: аннуитетный или дифференцированный
+ALTER TABLE credits
+    ADD COLUMN repayment_type VARCHAR(20) NOT NULL DEFAULT 'ANNUITY' CHECK (repayment_type IN ('ANNUITY', 'DIFFERENTIATED'));
@@ -0,0 +1,6 @@
+-- Связь транзакций с кредитом и платежом по графику
+ALTER TABLE transactions
+    ADD COLUMN credit_id INTEGER REFERENCES credits(id),
+    ADD COLUMN payment_schedule_id INTEGER REFERENCES payment_schedules(id);
+
+CREATE INDEX idx_transactions_credit_id ON transactions(credit_id);
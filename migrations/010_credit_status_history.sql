@@ -0,0 +1,17 @@
+-- История статусов кредита
+CREATE TABLE credit_status_history (
+    id SERIAL PRIMARY KEY,
+    credit_id INTEGER NOT NULL REFERENCES credits(id),
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_credit_status_history_credit_id ON credit_status_history(credit_id);
+
+-- Выданные ранее кредиты считаются действующими
+UPDATE credits SET status = 'ACTIVE', updated_at = NOW() WHERE status = 'APPROVED';
+
+INSERT INTO credit_status_history (credit_id, from_status, to_status, reason, changed_at)
+SELECT id, NULL, status, 'status before history tracking', updated_at FROM credits;
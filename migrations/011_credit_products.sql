@@ -0,0 +1,35 @@
+-- Каталог кредитных продуктов
+CREATE TABLE credit_products (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    rate_spread NUMERIC(5, 2) NOT NULL, -- надбавка к ключевой ставке ЦБ РФ
+    min_amount NUMERIC(15, 2) NOT NULL,
+    max_amount NUMERIC(15, 2) NOT NULL,
+    min_term INTEGER NOT NULL, -- в месяцах
+    max_term INTEGER NOT NULL,
+    issuance_fee_rate NUMERIC(5, 2) NOT NULL DEFAULT 0, -- процент от суммы кредита
+    issuance_fee_fixed NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    min_income NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    max_active_credits INTEGER NOT NULL DEFAULT 0, -- 0 - без ограничения
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (min_amount > 0 AND min_amount <= max_amount),
+    CHECK (min_term > 0 AND min_term <= max_term)
+);
+
+INSERT INTO credit_products (code, name, description, rate_spread, min_amount, max_amount, min_term, max_term, issuance_fee_rate, min_income, max_active_credits)
+VALUES
+    ('CONSUMER', 'Потребительский кредит', 'Кредит на любые цели', 5.00, 10000, 3000000, 3, 60, 0, 0, 0),
+    ('CAR', 'Автокредит', 'Кредит на покупку автомобиля', 3.50, 100000, 5000000, 12, 84, 1.00, 30000, 3),
+    ('MORTGAGE', 'Ипотека', 'Кредит на покупку жилья', 2.00, 500000, 30000000, 36, 360, 0.50, 50000, 1);
+
+ALTER TABLE credits
+    ADD COLUMN product_id INTEGER REFERENCES credit_products(id),
+    ADD COLUMN issuance_fee NUMERIC(15, 2) NOT NULL DEFAULT 0;
+
+UPDATE credits SET product_id = (SELECT id FROM credit_products WHERE code = 'CONSUMER');
+
+ALTER TABLE credits ALTER COLUMN product_id SET NOT NULL;
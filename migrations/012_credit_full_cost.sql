@@ -0,0 +1,4 @@
+-- Полная стоимость кредита (ПСК)
+ALTER TABLE credits
+    ADD COLUMN full_cost_rate NUMERIC(7, 3) NOT NULL DEFAULT 0,
+    ADD COLUMN full_cost_amount NUMERIC(15, 2) NOT NULL DEFAULT 0;
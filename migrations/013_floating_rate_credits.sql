@@ -0,0 +1,37 @@
+-- Кредиты с плавающей ставкой: ключевая ставка ЦБ РФ плюс надбавка
+ALTER TABLE credit_products
+    ADD COLUMN is_floating BOOLEAN NOT NULL DEFAULT FALSE;
+
+INSERT INTO credit_products (code, name, description, rate_spread, min_amount, max_amount, min_term, max_term, issuance_fee_rate, min_income, max_active_credits, is_floating)
+VALUES ('CONSUMER_FLOATING', 'Потребительский кредит с плавающей ставкой', 'Ставка пересматривается при изменении ключевой ставки ЦБ РФ', 4.00, 10000, 3000000, 12, 60, 0, 0, 0, TRUE);
+
+ALTER TABLE credits
+    ADD COLUMN is_floating BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN key_rate NUMERIC(5, 2) NOT NULL DEFAULT 0, -- ключевая ставка, по которой рассчитана текущая ставка
+    ADD COLUMN rate_spread NUMERIC(5, 2) NOT NULL DEFAULT 0;
+
+-- Для выданных ранее кредитов ключевая ставка восстанавливается из ставки кредита
+UPDATE credits c
+SET rate_spread = p.rate_spread,
+    key_rate = c.interest_rate - c.rate_adjustment - p.rate_spread
+FROM credit_products p
+WHERE p.id = c.product_id;
+
+-- История ставок по кредиту
+CREATE TABLE credit_rate_history (
+    id SERIAL PRIMARY KEY,
+    credit_id INTEGER NOT NULL REFERENCES credits(id),
+    key_rate NUMERIC(5, 2) NOT NULL,
+    interest_rate NUMERIC(5, 2) NOT NULL,
+    monthly_payment NUMERIC(15, 2) NOT NULL,
+    effective_from TIMESTAMP NOT NULL, -- дата первого платежа по новой ставке
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_credit_rate_history_credit_id ON credit_rate_history(credit_id);
+
+INSERT INTO credit_rate_history (credit_id, key_rate, interest_rate, monthly_payment, effective_from, reason, changed_at)
+SELECT id, key_rate, interest_rate, monthly_payment, start_date, 'credit issued', start_date
+FROM credits
+WHERE status IN ('ACTIVE', 'OVERDUE', 'CLOSED');
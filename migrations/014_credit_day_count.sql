@@ -0,0 +1,20 @@
+-- База начисления процентов и ежедневное начисление процентов
+ALTER TABLE credit_products
+    ADD COLUMN day_count VARCHAR(20) NOT NULL DEFAULT '30/360'; -- 30/360, ACTUAL/365, ACTUAL/ACTUAL
+
+UPDATE credit_products SET day_count = 'ACTUAL/365' WHERE code = 'MORTGAGE';
+
+ALTER TABLE credits
+    ADD COLUMN day_count VARCHAR(20) NOT NULL DEFAULT '30/360';
+
+CREATE TABLE credit_interest_accruals (
+    id SERIAL PRIMARY KEY,
+    credit_id INTEGER NOT NULL REFERENCES credits(id),
+    accrual_date DATE NOT NULL,
+    principal NUMERIC(15, 2) NOT NULL,
+    interest_rate NUMERIC(5, 2) NOT NULL,
+    day_count VARCHAR(20) NOT NULL,
+    amount NUMERIC(15, 6) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (credit_id, accrual_date)
+);
@@ -0,0 +1,19 @@
+-- Кредитные каникулы и реструктуризация
+CREATE TABLE credit_restructurings (
+    id SERIAL PRIMARY KEY,
+    credit_id INTEGER NOT NULL REFERENCES credits(id),
+    type VARCHAR(20) NOT NULL, -- PAYMENT_HOLIDAY, EXTEND_TERM
+    months INTEGER NOT NULL, -- длительность каникул или продление срока
+    principal_before NUMERIC(15, 2) NOT NULL,
+    capitalized_interest NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    principal_after NUMERIC(15, 2) NOT NULL,
+    term_before INTEGER NOT NULL,
+    term_after INTEGER NOT NULL,
+    monthly_payment_before NUMERIC(15, 2) NOT NULL,
+    monthly_payment_after NUMERIC(15, 2) NOT NULL,
+    first_payment_date TIMESTAMP NOT NULL, -- первый платеж по новому графику
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_credit_restructurings_credit_id ON credit_restructurings(credit_id);
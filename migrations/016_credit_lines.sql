@@ -0,0 +1,41 @@
+-- Возобновляемые кредитные линии на счетах типа CREDIT
+ALTER TABLE accounts
+    ADD COLUMN credit_limit NUMERIC(15, 2) NOT NULL DEFAULT 0 CHECK (credit_limit >= 0),
+    ADD COLUMN credit_line_suspended BOOLEAN NOT NULL DEFAULT FALSE; -- лимит недоступен до внесения минимального платежа
+
+-- Ежедневное начисление процентов на использованную часть лимита
+CREATE TABLE credit_line_accruals (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(id),
+    accrual_date DATE NOT NULL,
+    used_amount NUMERIC(15, 2) NOT NULL,
+    interest_rate NUMERIC(5, 2) NOT NULL,
+    amount NUMERIC(15, 6) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (account_id, accrual_date)
+);
+
+-- Ежемесячные выписки по кредитной линии
+CREATE TABLE credit_line_statements (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(id),
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    opening_balance NUMERIC(15, 2) NOT NULL,
+    closing_balance NUMERIC(15, 2) NOT NULL,
+    debits NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    credits NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    accrued_interest NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    interest NUMERIC(15, 2) NOT NULL DEFAULT 0, -- списанные проценты (0, если задолженность погашена до даты платежа)
+    debt NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    minimum_payment NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    due_date TIMESTAMP NOT NULL,
+    paid_amount NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    late_fee NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL, -- OPEN, PAID, MINIMUM_PAID, OVERDUE
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_credit_line_statements_account_id ON credit_line_statements(account_id);
+CREATE INDEX idx_credit_line_statements_status ON credit_line_statements(status);
@@ -0,0 +1,34 @@
+-- Работа с просроченной задолженностью: корзины по дням просрочки и эскалация
+CREATE TABLE credit_collection_cases (
+    id SERIAL PRIMARY KEY,
+    credit_id INTEGER NOT NULL REFERENCES credits(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    days_past_due INTEGER NOT NULL,
+    bucket VARCHAR(20) NOT NULL, -- DPD_1_30, DPD_31_60, DPD_61_90, DPD_90_PLUS
+    overdue_amount NUMERIC(15, 2) NOT NULL,
+    overdue_since TIMESTAMP NOT NULL, -- дата самого раннего просроченного платежа
+    status VARCHAR(20) NOT NULL, -- OPEN, RESOLVED
+    cards_blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP
+);
+
+-- По кредиту может быть только одно открытое дело
+CREATE UNIQUE INDEX idx_credit_collection_cases_open ON credit_collection_cases(credit_id) WHERE status = 'OPEN';
+CREATE INDEX idx_credit_collection_cases_user_id ON credit_collection_cases(user_id);
+
+-- Меры, принятые по делу
+CREATE TABLE credit_collection_actions (
+    id SERIAL PRIMARY KEY,
+    case_id INTEGER NOT NULL REFERENCES credit_collection_cases(id),
+    bucket VARCHAR(20) NOT NULL,
+    action VARCHAR(30) NOT NULL, -- REMINDER, ESCALATION_FINE, CARDS_BLOCKED, FINAL_NOTICE, RESOLVED
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_credit_collection_actions_case_id ON credit_collection_actions(case_id);
+
+-- Карты, заблокированные из-за просрочки, разблокируются после ее погашения
+ALTER TABLE cards ADD COLUMN blocked_by_collections BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+-- Ежемесячный доход, указанный пользователем в профиле (0 - не указан)
+ALTER TABLE users
+    ADD COLUMN declared_income NUMERIC(15, 2) NOT NULL DEFAULT 0 CHECK (declared_income >= 0),
+    ADD COLUMN income_declared_at TIMESTAMP;
@@ -0,0 +1,12 @@
+-- Последняя ключевая ставка, полученная от ЦБ РФ. Общая для всех экземпляров сервиса,
+-- используется, пока не истек срок кэша, и как запасная ставка, если ЦБ РФ недоступен.
+CREATE TABLE cbr_key_rate_cache (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    rate NUMERIC(5, 2) NOT NULL,
+    rate_date DATE NOT NULL, -- дата, с которой действует ставка
+    fetched_at TIMESTAMP NOT NULL
+);
+
+-- Кредит рассчитан по устаревшей ставке: ЦБ РФ не ответил, использована сохраненная или резервная ставка
+ALTER TABLE credits
+    ADD COLUMN key_rate_stale BOOLEAN NOT NULL DEFAULT FALSE;
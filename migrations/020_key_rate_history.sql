@@ -0,0 +1,7 @@
+-- История ключевой ставки ЦБ РФ: ставка действует с rate_date до следующей записи.
+-- Обновляется ежедневно и используется для расчета кредитов по ставке на дату выдачи.
+CREATE TABLE cbr_key_rates (
+    rate_date DATE PRIMARY KEY,
+    rate NUMERIC(5, 2) NOT NULL,
+    fetched_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,22 @@
+-- Карта, которой оплачена операция. Оспорить можно только операции с заполненной картой.
+ALTER TABLE transactions ADD COLUMN card_id INTEGER REFERENCES cards(id);
+
+-- Оплаты с терминалов связаны с картой через холд
+UPDATE transactions t
+SET card_id = h.card_id
+FROM card_holds h
+WHERE h.transaction_id = t.id;
+
+-- Оплаты картой через API проводились с описанием "Card payment" со счета единственной
+-- карты; если к счету выпущено несколько карт, операция остается без привязки
+UPDATE transactions t
+SET card_id = c.id
+FROM cards c
+WHERE t.card_id IS NULL
+    AND t.type = 'PAYMENT'
+    AND t.description = 'Card payment'
+    AND t.credit_id IS NULL
+    AND c.account_id = t.from_account_id
+    AND (SELECT COUNT(*) FROM cards c2 WHERE c2.account_id = c.account_id) = 1;
+
+CREATE INDEX idx_transactions_card_id ON transactions(card_id);
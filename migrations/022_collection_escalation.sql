@@ -0,0 +1,5 @@
+-- Старшая корзина, меры которой уже применены по делу: повторный переход в корзину
+-- после снижения просрочки не приводит к повторному штрафу и уведомлению
+ALTER TABLE credit_collection_cases ADD COLUMN escalated_bucket VARCHAR(20) NOT NULL DEFAULT '';
+
+UPDATE credit_collection_cases SET escalated_bucket = bucket;
@@ -0,0 +1,4 @@
+-- Блокировка из-за просрочки больше не снимает признак is_active: карты проверяют
+-- blocked_by_collections отдельно. Раньше блокировались только активные карты, поэтому
+-- заблокированным картам возвращается is_active.
+UPDATE cards SET is_active = TRUE WHERE blocked_by_collections = TRUE;
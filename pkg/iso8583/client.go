@@ -0,0 +1,100 @@
+package iso8583
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Client - простой клиент для отправки запросов на ISO 8583 листенер,
+// предназначен для тестирования интеграции с POS и эквайрингом.
+type Client struct {
+	conn    net.Conn
+	timeout time.Duration
+	mu      sync.Mutex
+	stan    uint32
+}
+
+func Dial(addr string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		conn:    conn,
+		timeout: timeout,
+	}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Send отправляет сообщение и ждет ответ. Запросы по одному соединению выполняются последовательно.
+func (c *Client) Send(request *Message) (*Message, error) {
+	data, err := request.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+
+	if err := WriteFrame(c.conn, data); err != nil {
+		return nil, err
+	}
+
+	frame, err := ReadFrame(c.conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return Unpack(frame)
+}
+
+// Authorize отправляет 0100 (предавторизация/холд)
+func (c *Client) Authorize(pan, expiry string, amount float64, rrn string) (*Message, error) {
+	return c.Send(c.NewCardRequest(MTIAuthorizationRequest, pan, expiry, amount, rrn))
+}
+
+// Purchase отправляет 0200 (финансовое сообщение)
+func (c *Client) Purchase(pan, expiry string, amount float64, rrn string) (*Message, error) {
+	return c.Send(c.NewCardRequest(MTIFinancialRequest, pan, expiry, amount, rrn))
+}
+
+// Reverse отправляет 0400 по RRN исходной операции
+func (c *Client) Reverse(pan, expiry string, amount float64, rrn string) (*Message, error) {
+	return c.Send(c.NewCardRequest(MTIReversalRequest, pan, expiry, amount, rrn))
+}
+
+// NewCardRequest заполняет типовой набор полей карточного запроса.
+// expiry передается в формате YYMM, как в поле 14.
+func (c *Client) NewCardRequest(mti, pan, expiry string, amount float64, rrn string) *Message {
+	now := time.Now()
+
+	message := NewMessage(mti)
+	message.Set(2, pan)
+	message.Set(3, "000000")
+	message.Set(4, FormatAmount(amount))
+	message.Set(7, now.Format("0102150405"))
+	message.Set(11, fmt.Sprintf("%06d", atomic.AddUint32(&c.stan, 1)%1000000))
+	message.Set(12, now.Format("150405"))
+	message.Set(13, now.Format("0102"))
+	if expiry != "" {
+		message.Set(14, expiry)
+	}
+	message.Set(22, "051")
+	message.Set(37, rrn)
+	message.Set(41, "TERM0001")
+	message.Set(42, "MERCHANT0000001")
+	message.Set(49, "643")
+
+	return message
+}
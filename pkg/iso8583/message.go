@@ -0,0 +1,249 @@
+package iso8583
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidMTI       = errors.New("iso8583: invalid MTI")
+	ErrUnsupportedField = errors.New("iso8583: unsupported field")
+	ErrMessageTooShort  = errors.New("iso8583: message too short")
+	ErrFrameTooLarge    = errors.New("iso8583: frame exceeds 65535 bytes")
+)
+
+// Message - сообщение ISO 8583 с MTI и набором полей в виде строк
+type Message struct {
+	MTI    string
+	fields map[int]string
+}
+
+func NewMessage(mti string) *Message {
+	return &Message{
+		MTI:    mti,
+		fields: make(map[int]string),
+	}
+}
+
+func (m *Message) Set(field int, value string) {
+	m.fields[field] = value
+}
+
+func (m *Message) Get(field int) string {
+	return m.fields[field]
+}
+
+func (m *Message) Has(field int) bool {
+	_, ok := m.fields[field]
+	return ok
+}
+
+// Response создает ответ с тем же набором идентифицирующих полей запроса
+func (m *Message) Response(responseCode string) *Message {
+	response := NewMessage(ResponseMTI(m.MTI))
+	for _, field := range []int{2, 3, 4, 7, 11, 12, 13, 37, 41, 42, 49} {
+		if m.Has(field) {
+			response.Set(field, m.Get(field))
+		}
+	}
+	response.Set(39, responseCode)
+	return response
+}
+
+// ResponseMTI возвращает MTI ответа на запрос (0100 -> 0110)
+func ResponseMTI(mti string) string {
+	if len(mti) != 4 {
+		return mti
+	}
+	return mti[:2] + string(mti[2]+1) + mti[3:]
+}
+
+// Pack кодирует сообщение: MTI, двоичный битмап (при необходимости вторичный) и поля по порядку
+func (m *Message) Pack() ([]byte, error) {
+	if len(m.MTI) != 4 || !isDigits(m.MTI) {
+		return nil, ErrInvalidMTI
+	}
+
+	numbers := make([]int, 0, len(m.fields))
+	for field := range m.fields {
+		if _, ok := fieldSpecs[field]; !ok {
+			return nil, fmt.Errorf("%w: %d", ErrUnsupportedField, field)
+		}
+		numbers = append(numbers, field)
+	}
+	sort.Ints(numbers)
+
+	bitmap := make([]byte, 8)
+	if len(numbers) > 0 && numbers[len(numbers)-1] > 64 {
+		bitmap = make([]byte, 16)
+		bitmap[0] |= 0x80
+	}
+
+	var body strings.Builder
+	for _, field := range numbers {
+		bitmap[(field-1)/8] |= 0x80 >> uint((field-1)%8)
+
+		encoded, err := encodeField(field, m.fields[field])
+		if err != nil {
+			return nil, err
+		}
+		body.WriteString(encoded)
+	}
+
+	data := make([]byte, 0, 4+len(bitmap)+body.Len())
+	data = append(data, m.MTI...)
+	data = append(data, bitmap...)
+	data = append(data, body.String()...)
+
+	return data, nil
+}
+
+// Unpack разбирает сообщение, закодированное Pack
+func Unpack(data []byte) (*Message, error) {
+	if len(data) < 12 {
+		return nil, ErrMessageTooShort
+	}
+
+	mti := string(data[:4])
+	if !isDigits(mti) {
+		return nil, ErrInvalidMTI
+	}
+
+	bitmap := data[4:12]
+	pos := 12
+	if bitmap[0]&0x80 != 0 {
+		if len(data) < 20 {
+			return nil, ErrMessageTooShort
+		}
+		bitmap = data[4:20]
+		pos = 20
+	}
+
+	message := NewMessage(mti)
+	for field := 2; field <= len(bitmap)*8; field++ {
+		if bitmap[(field-1)/8]&(0x80>>uint((field-1)%8)) == 0 {
+			continue
+		}
+
+		spec, ok := fieldSpecs[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrUnsupportedField, field)
+		}
+
+		length := spec.length
+		if spec.lengthType != fixed {
+			digits := 2
+			if spec.lengthType == lllvar {
+				digits = 3
+			}
+
+			if pos+digits > len(data) {
+				return nil, ErrMessageTooShort
+			}
+
+			parsed, err := strconv.Atoi(string(data[pos : pos+digits]))
+			if err != nil || parsed > spec.length {
+				return nil, fmt.Errorf("iso8583: invalid length of field %d", field)
+			}
+			length = parsed
+			pos += digits
+		}
+
+		if pos+length > len(data) {
+			return nil, ErrMessageTooShort
+		}
+
+		value := string(data[pos : pos+length])
+		if spec.lengthType == fixed && !spec.numeric {
+			value = strings.TrimRight(value, " ")
+		}
+
+		message.Set(field, value)
+		pos += length
+	}
+
+	return message, nil
+}
+
+// WriteFrame пишет сообщение с двухбайтовым заголовком длины (big-endian)
+func WriteFrame(w io.Writer, data []byte) error {
+	if len(data) > 0xFFFF {
+		return ErrFrameTooLarge
+	}
+
+	header := make([]byte, 2)
+	binary.BigEndian.PutUint16(header, uint16(len(data)))
+
+	if _, err := w.Write(append(header, data...)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReadFrame читает одно сообщение с двухбайтовым заголовком длины
+func ReadFrame(r io.Reader) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint16(header))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// FormatAmount переводит сумму в рублях в значение поля 4 (копейки)
+func FormatAmount(amount float64) string {
+	return fmt.Sprintf("%012d", int64(amount*100+0.5))
+}
+
+// ParseAmount переводит значение поля 4 в рубли
+func ParseAmount(value string) (float64, error) {
+	minor, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(minor) / 100, nil
+}
+
+func encodeField(field int, value string) (string, error) {
+	spec := fieldSpecs[field]
+
+	if spec.numeric && !isDigits(value) {
+		return "", fmt.Errorf("iso8583: field %d must be numeric", field)
+	}
+
+	if len(value) > spec.length {
+		return "", fmt.Errorf("iso8583: field %d exceeds %d characters", field, spec.length)
+	}
+
+	switch spec.lengthType {
+	case llvar:
+		return fmt.Sprintf("%02d%s", len(value), value), nil
+	case lllvar:
+		return fmt.Sprintf("%03d%s", len(value), value), nil
+	}
+
+	if spec.numeric {
+		return strings.Repeat("0", spec.length-len(value)) + value, nil
+	}
+	return value + strings.Repeat(" ", spec.length-len(value)), nil
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package iso8583
+
+type lengthType int
+
+const (
+	fixed lengthType = iota
+	llvar
+	lllvar
+)
+
+type fieldSpec struct {
+	length     int
+	lengthType lengthType
+	numeric    bool
+}
+
+// Поддерживаемое подмножество полей ISO 8583:1987 в ASCII-кодировке
+var fieldSpecs = map[int]fieldSpec{
+	2:  {length: 19, lengthType: llvar, numeric: true},  // Primary account number
+	3:  {length: 6, lengthType: fixed, numeric: true},   // Processing code
+	4:  {length: 12, lengthType: fixed, numeric: true},  // Amount, transaction (в копейках)
+	7:  {length: 10, lengthType: fixed, numeric: true},  // Transmission date and time, MMDDhhmmss
+	11: {length: 6, lengthType: fixed, numeric: true},   // System trace audit number
+	12: {length: 6, lengthType: fixed, numeric: true},   // Local transaction time, hhmmss
+	13: {length: 4, lengthType: fixed, numeric: true},   // Local transaction date, MMDD
+	14: {length: 4, lengthType: fixed, numeric: true},   // Expiration date, YYMM
+	22: {length: 3, lengthType: fixed, numeric: true},   // POS entry mode
+	37: {length: 12, lengthType: fixed, numeric: false}, // Retrieval reference number
+	38: {length: 6, lengthType: fixed, numeric: false},  // Authorization identification response
+	39: {length: 2, lengthType: fixed, numeric: false},  // Response code
+	41: {length: 8, lengthType: fixed, numeric: false},  // Card acceptor terminal identification
+	42: {length: 15, lengthType: fixed, numeric: false}, // Card acceptor identification code
+	43: {length: 40, lengthType: fixed, numeric: false}, // Card acceptor name/location
+	49: {length: 3, lengthType: fixed, numeric: true},   // Currency code, transaction
+	90: {length: 42, lengthType: fixed, numeric: true},  // Original data elements
+}
+
+// Типы сообщений
+const (
+	MTIAuthorizationRequest  = "0100"
+	MTIAuthorizationResponse = "0110"
+	MTIFinancialRequest      = "0200"
+	MTIFinancialResponse     = "0210"
+	MTIReversalRequest       = "0400"
+	MTIReversalResponse      = "0410"
+)
+
+// Коды ответа (поле 39)
+const (
+	ResponseApproved           = "00"
+	ResponseDoNotHonor         = "05"
+	ResponseInvalidTransaction = "12"
+	ResponseInvalidAmount      = "13"
+	ResponseInvalidCardNumber  = "14"
+	ResponseOriginalNotFound   = "25"
+	ResponseFormatError        = "30"
+	ResponseInsufficientFunds  = "51"
+	ResponseExpiredCard        = "54"
+	ResponseRestrictedCard     = "62"
+	ResponseDuplicate          = "94"
+	ResponseSystemMalfunction  = "96"
+)